@@ -0,0 +1,40 @@
+package oops
+
+// Attr is a key-value pair attached to an *[Error] using [With] or [Sensitive].
+type Attr struct {
+	Key       string
+	Value     any
+	Sensitive bool // redacted in formatting, JSON encoding and log output
+}
+
+// With attaches an attribute to the *[Error], e.g. an entity ID or a request parameter.
+// Attaching an existing key overwrites its value, and whether it is [Sensitive].
+func With(key string, value any) ErrorOption {
+	return func(err *Error) {
+		for i := range err.attrs {
+			if err.attrs[i].Key == key {
+				err.attrs[i] = Attr{Key: key, Value: value}
+				return
+			}
+		}
+		err.attrs = append(err.attrs, Attr{Key: key, Value: value})
+	}
+}
+
+// Attr returns the value of the attribute with the given key, and whether it exists.
+func (err *Error) Attr(key string) (any, bool) {
+	for i := range err.attrs {
+		if err.attrs[i].Key == key {
+			return err.attrs[i].Value, true
+		}
+	}
+	return nil, false
+}
+
+// Attrs returns a copy of the attributes of the *[Error], in the order they were attached.
+func (err *Error) Attrs() []Attr {
+	if len(err.attrs) == 0 {
+		return nil
+	}
+	return append([]Attr(nil), err.attrs...)
+}
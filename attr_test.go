@@ -0,0 +1,41 @@
+package oops_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/piteego/oops"
+	"github.com/piteego/oops/example"
+)
+
+func TestWith(t *testing.T) {
+	err := oops.New("user not found",
+		oops.Tag(example.NotFound.Error),
+		oops.With("user_id", 42),
+		oops.With("tenant", "acme"),
+		oops.With("user_id", 43),
+	).(*oops.Error)
+	if got, ok := err.Attr("user_id"); !ok || got != 43 {
+		t.Errorf("expected overwritten attribute 43, got %v", got)
+	}
+	if _, ok := err.Attr("missing"); ok {
+		t.Errorf("expected missing attribute not to exist")
+	}
+	attrs := err.Attrs()
+	if len(attrs) != 2 || attrs[0].Key != "user_id" || attrs[1].Key != "tenant" {
+		t.Errorf("expected attributes in order, got %v", attrs)
+	}
+	attrs[0].Value = "modified"
+	if got, _ := err.Attr("user_id"); got != 43 {
+		t.Errorf("expected Attrs to return a copy, got %v", got)
+	}
+	if verbose := fmt.Sprintf("%+v", err); !strings.Contains(verbose, "    attrs:\n        user_id: 43\n        tenant: acme") {
+		t.Errorf("expected attributes in %q", verbose)
+	}
+	data, _ := json.Marshal(err)
+	if !strings.Contains(string(data), `"attrs":{"tenant":"acme","user_id":43}`) {
+		t.Errorf("expected attributes in %s", data)
+	}
+}
@@ -0,0 +1,64 @@
+package oops
+
+import "context"
+
+// Builder builds *[Error] values fluently:
+//
+//	oops.B().Tag(example.NotFound.Error).Because(err).With("id", id).Msg("user not found")
+//
+// A Builder is immutable: each step returns a new Builder and leaves the receiver untouched,
+// so partial builders can be stored as package-level templates and shared across goroutines:
+//
+//	var notFound = oops.B().Tag(example.NotFound.Error)
+//
+//	return notFound.Because(err).Msg("user not found")
+//
+// The zero value is an empty Builder, ready to use.
+type Builder struct {
+	options []ErrorOption
+}
+
+// B returns an empty [Builder].
+func B() Builder { return Builder{} }
+
+// with returns a new Builder with the option appended, never sharing the backing array of b.
+func (b Builder) with(option ErrorOption) Builder {
+	return Builder{options: append(b.options[:len(b.options):len(b.options)], option)}
+}
+
+// Tag returns a new [Builder] that tags the *[Error] with a [Label], see [Tag].
+func (b Builder) Tag(label Label) Builder { return b.with(Tag(label)) }
+
+// Because returns a new [Builder] that appends the causes to the *[Error], see [Because].
+func (b Builder) Because(causes ...error) Builder {
+	return b.with(Because(append([]error(nil), causes...)...))
+}
+
+// With returns a new [Builder] that attaches an attribute to the *[Error], see [With].
+func (b Builder) With(key string, value any) Builder { return b.with(With(key, value)) }
+
+// Sensitive returns a new [Builder] that attaches a sensitive attribute to the *[Error], see [Sensitive].
+func (b Builder) Sensitive(key string, value any) Builder { return b.with(Sensitive(key, value)) }
+
+// Bind returns a new [Builder] that binds a value to a placeholder of the message template, see [Bind].
+func (b Builder) Bind(name string, value any) Builder { return b.with(Bind(name, value)) }
+
+// Ctx returns a new [Builder] that stamps the *[Error] with the correlation identifiers active in ctx,
+// see [FromContext].
+func (b Builder) Ctx(ctx context.Context) Builder { return b.with(FromContext(ctx)) }
+
+// Options returns a new [Builder] that also applies the given options, in order.
+func (b Builder) Options(options ...ErrorOption) Builder {
+	return Builder{options: append(b.options[:len(b.options):len(b.options)], options...)}
+}
+
+// Msg creates a new *[Error] with the given message, applying the steps of the [Builder] as options of [New].
+func (b Builder) Msg(msg string) error {
+	return newError(1, msg, b.options)
+}
+
+// Msgf creates a new *[Error] with the given message template and args as [Newf] does,
+// applying the steps of the [Builder] first.
+func (b Builder) Msgf(template string, args ...any) error {
+	return newf(1, template, args, b.options)
+}
@@ -0,0 +1,132 @@
+package oops_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/piteego/oops"
+	"github.com/piteego/oops/example"
+)
+
+func TestBuilder(t *testing.T) {
+	cause := errors.New("connection refused")
+	err := oops.B().
+		Tag(example.Internal.Error).
+		Because(cause).
+		With("host", "db-1").
+		Sensitive("password", "hunter2").
+		Bind("name", "orders").
+		Msg("database {name} unavailable")
+	var oopsErr *oops.Error
+	if !errors.As(err, &oopsErr) {
+		t.Fatalf("expected an *oops.Error, got %T", err)
+	}
+	if got, want := err.Error(), "database orders unavailable"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+	if !errors.Is(err, example.Internal.Error) || !errors.Is(err, cause) {
+		t.Errorf("expected err to match its label and cause")
+	}
+	if value, ok := oopsErr.Attr("host"); !ok || value != "db-1" {
+		t.Errorf("expected host attr, got %v", value)
+	}
+	if attrs := oopsErr.Attrs(); len(attrs) != 2 || !attrs[1].Sensitive {
+		t.Errorf("expected a sensitive password attr, got %v", attrs)
+	}
+}
+
+func TestBuilder_zeroValue(t *testing.T) {
+	var b oops.Builder
+	err := b.Msg("failed")
+	if !errors.Is(err, oops.Untagged) {
+		t.Errorf("expected an untagged error, got %v", err.(*oops.Error).Label())
+	}
+}
+
+func TestBuilder_immutable(t *testing.T) {
+	base := oops.B().Tag(example.NotFound.Error).With("a", 1)
+	// Both branches append to base: neither must see the step of the other.
+	left := base.With("b", 2)
+	right := base.With("c", 3)
+	for _, tc := range []struct {
+		name string
+		b    oops.Builder
+		want []string
+	}{
+		{name: "base", b: base, want: []string{"a"}},
+		{name: "left", b: left, want: []string{"a", "b"}},
+		{name: "right", b: right, want: []string{"a", "c"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			attrs := tc.b.Msg("failed").(*oops.Error).Attrs()
+			if len(attrs) != len(tc.want) {
+				t.Fatalf("expected attrs %v, got %v", tc.want, attrs)
+			}
+			for i := range attrs {
+				if attrs[i].Key != tc.want[i] {
+					t.Errorf("expected attrs %v, got %v", tc.want, attrs)
+				}
+			}
+		})
+	}
+}
+
+func TestBuilder_becauseCopiesCauses(t *testing.T) {
+	causes := []error{errors.New("first")}
+	b := oops.B().Because(causes...)
+	causes[0] = errors.New("second")
+	if got := b.Msg("failed").(*oops.Error).Causes(); got[0].Error() != "first" {
+		t.Errorf("expected the cause given to Because, got %v", got)
+	}
+}
+
+func TestBuilder_Msgf(t *testing.T) {
+	err := oops.B().Tag(example.NotFound.Error).Msgf("user {id} not found", 42, oops.With("table", "users"))
+	oopsErr := err.(*oops.Error)
+	if got, want := err.Error(), "user 42 not found"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+	if oopsErr.Label() != example.NotFound.Error {
+		t.Errorf("expected label %v, got %v", example.NotFound.Error, oopsErr.Label())
+	}
+	if _, ok := oopsErr.Attr("table"); !ok {
+		t.Errorf("expected the table attr")
+	}
+}
+
+func TestBuilder_Ctx(t *testing.T) {
+	ctx := oops.ContextWithRequestID(context.Background(), "req-1")
+	err := oops.B().Ctx(ctx).Msg("failed").(*oops.Error)
+	if got := err.RequestID(); got != "req-1" {
+		t.Errorf("expected request id %q, got %q", "req-1", got)
+	}
+}
+
+func TestBuilder_fingerprintIsCallSite(t *testing.T) {
+	b := oops.B().Tag(example.Internal.Error)
+	newErr := func() error { return b.Msg("failed") }
+	if newErr().(*oops.Error).Fingerprint() != newErr().(*oops.Error).Fingerprint() {
+		t.Errorf("expected errors created at the same call site to share a fingerprint")
+	}
+	if b.Msg("failed").(*oops.Error).Fingerprint() == newErr().(*oops.Error).Fingerprint() {
+		t.Errorf("expected errors created at different call sites to differ")
+	}
+}
+
+func TestBuilder_concurrent(t *testing.T) {
+	shared := oops.B().Tag(example.Internal.Error).With("service", "orders")
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			err := shared.With("worker", i).Because(errors.New("boom")).Msg("failed").(*oops.Error)
+			if attrs := err.Attrs(); len(attrs) != 2 || attrs[1].Value != i {
+				t.Errorf("expected worker %d, got %v", i, attrs)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
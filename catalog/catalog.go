@@ -0,0 +1,129 @@
+// Package catalog documents the errors clients of an application may see, from the labels registered with [oops.Register]
+// and the maps registered with [oops.RegisterMap] at program init.
+// The catalog is exported as a Markdown table, a JSON document, or OpenAPI components/responses.
+//
+// [Main] provides a command line interface; build it into a command importing the packages that register your labels:
+//
+//	package main
+//
+//	import (
+//		"github.com/piteego/oops/catalog"
+//		_ "example.com/app/labels"
+//	)
+//
+//	func main() { catalog.Main() }
+package catalog
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/piteego/oops"
+)
+
+// Entry documents a registered [oops.Label].
+type Entry struct {
+	Code        string   `json:"code"`
+	Label       string   `json:"label"`
+	Description string   `json:"description,omitempty"`
+	HTTPStatus  int      `json:"http_status,omitempty"`
+	GRPCCode    int      `json:"grpc_code,omitempty"`
+	Examples    []string `json:"examples"`
+}
+
+// Catalog lists the registered labels, sorted by code.
+type Catalog struct {
+	Entries []Entry `json:"labels"`
+}
+
+// Build returns the [Catalog] of the registered labels. The examples of an entry are the messages
+// of the *[oops.Error] values of the registered maps tagged with its label, or the label message if there are none.
+func Build() Catalog {
+	examples := make(map[oops.Label]map[string]struct{})
+	for _, m := range oops.Maps() {
+		for _, oopsErr := range m {
+			if oopsErr == nil {
+				continue
+			}
+			label := oopsErr.Label()
+			if examples[label] == nil {
+				examples[label] = make(map[string]struct{})
+			}
+			examples[label][oopsErr.Error()] = struct{}{}
+		}
+	}
+	defs := oops.Definitions()
+	c := Catalog{Entries: make([]Entry, 0, len(defs))}
+	for _, def := range defs {
+		e := Entry{
+			Code:        def.Code,
+			Label:       def.Label.Error(),
+			Description: def.Description,
+			HTTPStatus:  def.HTTPStatus,
+			GRPCCode:    def.GRPCCode,
+		}
+		for msg := range examples[def.Label] {
+			e.Examples = append(e.Examples, msg)
+		}
+		sort.Strings(e.Examples)
+		if len(e.Examples) == 0 {
+			e.Examples = []string{e.Label}
+		}
+		c.Entries = append(c.Entries, e)
+	}
+	return c
+}
+
+// Write writes the [Catalog] in the given format: "markdown", "json" or "openapi".
+func (c Catalog) Write(w io.Writer, format string) error {
+	switch format {
+	case "markdown", "md":
+		return c.WriteMarkdown(w)
+	case "json":
+		return c.WriteJSON(w)
+	case "openapi":
+		return c.WriteOpenAPI(w)
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+}
+
+// Main runs the catalog command line interface and exits:
+//
+//	-format markdown|json|openapi   output format (default markdown)
+//	-o file                         output file (default standard output)
+func Main() {
+	if err := run(os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintf(os.Stderr, "catalog: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("catalog", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	format := fs.String("format", "markdown", "output `format`: markdown, json or openapi")
+	out := fs.String("o", "", "output `file` (default standard output)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return errors.New("unexpected arguments")
+	}
+	if *out == "" {
+		return Build().Write(stdout, *format)
+	}
+	f, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	if err := Build().Write(f, *format); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,75 @@
+package catalog
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	_ "github.com/piteego/oops/example"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestBuild(t *testing.T) {
+	c := Build()
+	if len(c.Entries) != 8 {
+		t.Fatalf("expected the 8 example labels, got %d", len(c.Entries))
+	}
+	notFound := c.Entries[6]
+	if notFound.Code != "30" || notFound.HTTPStatus != 404 || strings.Join(notFound.Examples, ",") != "cache key not found,entity not found" {
+		t.Errorf("unexpected entry %+v", notFound)
+	}
+	if internal := c.Entries[1]; len(internal.Examples) != 1 || internal.Examples[0] != internal.Label {
+		t.Errorf("expected label message as example, got %+v", internal)
+	}
+}
+
+func TestCatalog_Write(t *testing.T) {
+	for _, format := range []string{"markdown", "json", "openapi"} {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Build().Write(&buf, format); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			golden := filepath.Join("testdata", format+".golden")
+			if *update {
+				if err := os.WriteFile(golden, buf.Bytes(), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if buf.String() != string(want) {
+				t.Errorf("output does not match %s:\n%s", golden, buf.String())
+			}
+		})
+	}
+	if err := Build().Write(io.Discard, "yaml"); err == nil {
+		t.Errorf("expected unknown format error")
+	}
+}
+
+func TestRun(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "catalog.json")
+	if err := run([]string{"-format", "json", "-o", out}, io.Discard, io.Discard); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, _ := os.ReadFile(out)
+	want, _ := os.ReadFile(filepath.Join("testdata", "json.golden"))
+	if string(got) != string(want) {
+		t.Errorf("unexpected output:\n%s", got)
+	}
+	var stdout bytes.Buffer
+	if err := run(nil, &stdout, io.Discard); err != nil || !strings.HasPrefix(stdout.String(), "| Code |") {
+		t.Errorf("expected markdown on standard output, got %v: %s", err, stdout.String())
+	}
+	if err := run([]string{"-format", "yaml"}, io.Discard, io.Discard); err == nil {
+		t.Errorf("expected unknown format error")
+	}
+}
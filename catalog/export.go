@@ -0,0 +1,97 @@
+package catalog
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+var cellEscaper = strings.NewReplacer("|", `\|`, "\n", " ")
+
+// WriteMarkdown writes the [Catalog] as a Markdown table.
+func (c Catalog) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("| Code | Label | HTTP status | gRPC code | Description | Example messages |\n")
+	b.WriteString("|------|-------|-------------|-----------|-------------|------------------|\n")
+	for _, e := range c.Entries {
+		status, grpc := "", ""
+		if e.HTTPStatus != 0 {
+			status = strings.TrimSpace(fmt.Sprintf("%d %s", e.HTTPStatus, http.StatusText(e.HTTPStatus)))
+		}
+		if e.GRPCCode != 0 {
+			grpc = fmt.Sprint(e.GRPCCode)
+		}
+		examples := make([]string, len(e.Examples))
+		for i := range e.Examples {
+			examples[i] = cellEscaper.Replace(e.Examples[i])
+		}
+		fmt.Fprintf(&b, "| `%s` | %s | %s | %s | %s | %s |\n",
+			e.Code, cellEscaper.Replace(e.Label), status, grpc, cellEscaper.Replace(e.Description), strings.Join(examples, "<br>"))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteJSON writes the [Catalog] as an indented JSON document.
+func (c Catalog) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(c)
+}
+
+// problem is the example body of an OpenAPI response, as RFC 9457 Problem Details.
+type problem struct {
+	Title  string `json:"title"`
+	Status int    `json:"status,omitempty"`
+	Detail string `json:"detail"`
+	Code   string `json:"code"`
+}
+
+type openAPIResponse struct {
+	Description string                      `json:"description"`
+	Content     map[string]openAPIMediaType `json:"content"`
+}
+
+type openAPIMediaType struct {
+	Schema  map[string]any `json:"schema"`
+	Example problem        `json:"example"`
+}
+
+var problemSchema = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"type":   map[string]string{"type": "string"},
+		"title":  map[string]string{"type": "string"},
+		"status": map[string]string{"type": "integer"},
+		"detail": map[string]string{"type": "string"},
+		"code":   map[string]string{"type": "string"},
+	},
+}
+
+// WriteOpenAPI writes the [Catalog] as OpenAPI components/responses, one per code, to be referenced as
+// "#/components/responses/<code>". The responses describe an application/problem+json body
+// whose example is the first example message of the entry.
+func (c Catalog) WriteOpenAPI(w io.Writer) error {
+	responses := make(map[string]openAPIResponse, len(c.Entries))
+	for _, e := range c.Entries {
+		description := e.Description
+		if description == "" {
+			description = e.Label
+		}
+		responses[e.Code] = openAPIResponse{
+			Description: description,
+			Content: map[string]openAPIMediaType{
+				"application/problem+json": {
+					Schema:  problemSchema,
+					Example: problem{Title: e.Label, Status: e.HTTPStatus, Detail: e.Examples[0], Code: e.Code},
+				},
+			},
+		}
+	}
+	doc := map[string]any{"components": map[string]any{"responses": responses}}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
@@ -0,0 +1,16 @@
+// Command oopscatalog prints the error catalog of the example package in Markdown, JSON or OpenAPI format.
+//
+// It demonstrates [catalog.Main]: copy it into your application, importing the packages that register
+// your labels and maps instead of the example package.
+//
+// Usage:
+//
+//	oopscatalog [-format markdown|json|openapi] [-o file]
+package main
+
+import (
+	"github.com/piteego/oops/catalog"
+	_ "github.com/piteego/oops/example"
+)
+
+func main() { catalog.Main() }
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/token"
+	"io"
+	"net/http"
+)
+
+// Catalog is the declarative definition of the labels of a package.
+type Catalog struct {
+	Package string  `json:"package"`
+	Labels  []Entry `json:"labels"`
+}
+
+// Entry defines a single label of a [Catalog].
+type Entry struct {
+	Name        string `json:"name"`    // exported Go variable name, e.g. "NotFound"
+	Code        string `json:"code"`    // stable unique code, e.g. "NOT_FOUND"
+	Message     string `json:"message"` // error message of the label
+	Description string `json:"description,omitempty"`
+	HTTPStatus  int    `json:"http_status,omitempty"`
+	GRPCCode    int    `json:"grpc_code,omitempty"`
+}
+
+// reserved holds the identifiers declared by the generated code, which label names must not clash with.
+var reserved = map[string]bool{"Labels": true, "ByCode": true, "Code": true, "HTTPStatus": true, "GRPCCode": true, "byCode": true}
+
+// decode reads and validates a JSON catalog.
+func decode(r io.Reader) (*Catalog, error) {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	var c Catalog
+	if err := dec.Decode(&c); err != nil {
+		return nil, fmt.Errorf("decoding catalog: %w", err)
+	}
+	return &c, c.validate()
+}
+
+func (c *Catalog) validate() error {
+	var errs []error
+	if c.Package != "" && !token.IsIdentifier(c.Package) {
+		errs = append(errs, fmt.Errorf("package %q is not a valid identifier", c.Package))
+	}
+	if len(c.Labels) == 0 {
+		errs = append(errs, errors.New("catalog has no labels"))
+	}
+	names, codes := make(map[string]int), make(map[string]int)
+	for i, e := range c.Labels {
+		at := fmt.Sprintf("labels[%d]", i)
+		if !token.IsIdentifier(e.Name) || !token.IsExported(e.Name) {
+			errs = append(errs, fmt.Errorf("%s: name %q is not an exported identifier", at, e.Name))
+		} else if reserved[e.Name] {
+			errs = append(errs, fmt.Errorf("%s: name %q is reserved by the generated code", at, e.Name))
+		}
+		if j, exists := names[e.Name]; exists {
+			errs = append(errs, fmt.Errorf("%s: name %q already used by labels[%d]", at, e.Name, j))
+		}
+		names[e.Name] = i
+		if e.Code == "" {
+			errs = append(errs, fmt.Errorf("%s: code is empty", at))
+		} else if j, exists := codes[e.Code]; exists {
+			errs = append(errs, fmt.Errorf("%s: code %q already used by labels[%d]", at, e.Code, j))
+		}
+		codes[e.Code] = i
+		if e.Message == "" {
+			errs = append(errs, fmt.Errorf("%s: message is empty", at))
+		}
+		if e.HTTPStatus != 0 && http.StatusText(e.HTTPStatus) == "" {
+			errs = append(errs, fmt.Errorf("%s: unknown http_status %d", at, e.HTTPStatus))
+		}
+		if e.GRPCCode < 0 || e.GRPCCode > 16 {
+			errs = append(errs, fmt.Errorf("%s: grpc_code %d is out of range [0, 16]", at, e.GRPCCode))
+		}
+	}
+	return errors.Join(errs...)
+}
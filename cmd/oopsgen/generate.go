@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"net/http"
+	"strings"
+	"text/template"
+)
+
+var goTemplate = template.Must(template.New("go").Funcs(template.FuncMap{"comment": comment}).Parse(`// Code generated by oopsgen from {{.Source}}. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"errors"
+
+	"github.com/piteego/oops"
+)
+
+var (
+{{- range .Labels}}
+	{{comment .}}
+	{{.Name}} oops.Label = errors.New({{printf "%q" .Message}})
+{{- end}}
+)
+
+func init() {
+	oops.Register(
+	{{- range .Labels}}
+		oops.Definition{Label: {{.Name}}, Code: {{printf "%q" .Code}}, Description: {{printf "%q" .Description}}, HTTPStatus: {{.HTTPStatus}}, GRPCCode: {{.GRPCCode}}},
+	{{- end}}
+	)
+}
+
+// byCode maps the codes of the catalog to their labels.
+var byCode = map[string]oops.Label{
+{{- range .Labels}}
+	{{printf "%q" .Code}}: {{.Name}},
+{{- end}}
+}
+
+// Labels returns the labels of the catalog, in the order they are defined.
+func Labels() []oops.Label {
+	return []oops.Label{ {{- range $i, $l := .Labels}}{{if $i}}, {{end}}{{$l.Name}}{{end -}} }
+}
+
+// ByCode returns the label of the catalog with the given code.
+func ByCode(code string) (oops.Label, bool) {
+	label, ok := byCode[code]
+	return label, ok
+}
+
+// Code returns the code of a label of the catalog, or an empty string.
+func Code(label oops.Label) string {
+	switch label {
+{{- range .Labels}}
+	case {{.Name}}:
+		return {{printf "%q" .Code}}
+{{- end}}
+	}
+	return ""
+}
+
+// HTTPStatus returns the HTTP status of a label of the catalog, or 0.
+func HTTPStatus(label oops.Label) int {
+	switch label {
+{{- range .Labels}}{{if .HTTPStatus}}
+	case {{.Name}}:
+		return {{.HTTPStatus}}
+{{- end}}{{end}}
+	}
+	return 0
+}
+
+// GRPCCode returns the gRPC status code of a label of the catalog, or 0.
+func GRPCCode(label oops.Label) int {
+	switch label {
+{{- range .Labels}}{{if .GRPCCode}}
+	case {{.Name}}:
+		return {{.GRPCCode}}
+{{- end}}{{end}}
+	}
+	return 0
+}
+`))
+
+// comment returns the doc comment of the variable of a label.
+func comment(e Entry) string {
+	text := e.Name + " is the label of code " + e.Code + "."
+	if e.Description != "" {
+		text += " " + e.Description
+	}
+	return "// " + strings.ReplaceAll(text, "\n", "\n\t// ")
+}
+
+// generateGo returns the formatted Go source of the catalog.
+func generateGo(c *Catalog, source string) ([]byte, error) {
+	var buf bytes.Buffer
+	data := struct {
+		*Catalog
+		Source string
+	}{c, source}
+	if err := goTemplate.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w", err)
+	}
+	return src, nil
+}
+
+// generateDoc returns the Markdown documentation of the catalog.
+func generateDoc(c *Catalog, source string) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "<!-- Code generated by oopsgen from %s. DO NOT EDIT. -->\n\n", source)
+	fmt.Fprintf(&buf, "# Labels of package %s\n\n", c.Package)
+	buf.WriteString("| Code | Label | Message | HTTP status | gRPC code | Description |\n")
+	buf.WriteString("|------|-------|---------|-------------|-----------|-------------|\n")
+	for _, e := range c.Labels {
+		status := ""
+		if e.HTTPStatus != 0 {
+			status = fmt.Sprintf("%d %s", e.HTTPStatus, http.StatusText(e.HTTPStatus))
+		}
+		grpc := ""
+		if e.GRPCCode != 0 {
+			grpc = fmt.Sprint(e.GRPCCode)
+		}
+		fmt.Fprintf(&buf, "| `%s` | `%s` | %s | %s | %s | %s |\n", e.Code, e.Name, cell(e.Message), status, grpc, cell(e.Description))
+	}
+	return buf.Bytes()
+}
+
+var cellEscaper = strings.NewReplacer("|", `\|`, "\n", " ")
+
+func cell(s string) string { return cellEscaper.Replace(s) }
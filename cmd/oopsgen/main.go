@@ -0,0 +1,83 @@
+// Command oopsgen generates the labels of a package from a declarative JSON catalog.
+//
+// The catalog lists the labels with their Go name, unique code, message, description, HTTP status and gRPC code:
+//
+//	{
+//	  "package": "labels",
+//	  "labels": [
+//	    {"name": "NotFound", "code": "NOT_FOUND", "message": "resource not found",
+//	     "description": "The requested resource was not found.", "http_status": 404, "grpc_code": 5}
+//	  ]
+//	}
+//
+// The generated Go file declares a label variable per entry, registers their definitions with oops.Register,
+// and provides the Labels, ByCode, Code, HTTPStatus and GRPCCode lookup functions, so labels cannot take these names.
+// Optionally, a Markdown table documenting the labels is generated as well.
+// The output only depends on the catalog, so regenerating is deterministic. Typical usage with go generate:
+//
+//	//go:generate go run github.com/piteego/oops/cmd/oopsgen -in labels.json -out labels_gen.go -doc LABELS.md
+//
+// The package name defaults to the one of the catalog, or to $GOPACKAGE as set by go generate.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stderr); err != nil {
+		fmt.Fprintf(os.Stderr, "oopsgen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stderr io.Writer) error {
+	fs := flag.NewFlagSet("oopsgen", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	in := fs.String("in", "", "path of the JSON `catalog`")
+	out := fs.String("out", "", "path of the generated Go `file`")
+	doc := fs.String("doc", "", "path of the generated Markdown `file` (optional)")
+	pkg := fs.String("package", "", "package `name` of the generated file (default: catalog package or $GOPACKAGE)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *in == "" || *out == "" {
+		fs.Usage()
+		return errors.New("-in and -out are required")
+	}
+	f, err := os.Open(*in)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	catalog, err := decode(f)
+	if err != nil {
+		return fmt.Errorf("%s: %w", *in, err)
+	}
+	switch {
+	case *pkg != "":
+		catalog.Package = *pkg
+	case catalog.Package == "":
+		catalog.Package = os.Getenv("GOPACKAGE")
+	}
+	if catalog.Package == "" {
+		return errors.New("package name is not set in the catalog, -package or $GOPACKAGE")
+	}
+	source := filepath.Base(*in)
+	src, err := generateGo(catalog, source)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(*out, src, 0o644); err != nil {
+		return err
+	}
+	if *doc != "" {
+		return os.WriteFile(*doc, generateDoc(catalog, source), 0o644)
+	}
+	return nil
+}
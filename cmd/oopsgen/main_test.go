@@ -0,0 +1,109 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	out, doc := filepath.Join(dir, "labels_gen.go"), filepath.Join(dir, "LABELS.md")
+	args := []string{"-in", filepath.Join("testdata", "labels.json"), "-out", out, "-doc", doc}
+	for range 2 { // regenerating is deterministic
+		if err := run(args, io.Discard); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		compare(t, out, filepath.Join("testdata", "labels_gen.go.golden"))
+		compare(t, doc, filepath.Join("testdata", "LABELS.md.golden"))
+	}
+}
+
+func compare(t *testing.T, got, want string) {
+	t.Helper()
+	gotData, err := os.ReadFile(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantData, err := os.ReadFile(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(gotData) != string(wantData) {
+		t.Errorf("%s does not match %s:\n%s", got, want, gotData)
+	}
+}
+
+func TestRun_Package(t *testing.T) {
+	dir := t.TempDir()
+	in := filepath.Join(dir, "labels.json")
+	out := filepath.Join(dir, "labels_gen.go")
+	if err := os.WriteFile(in, []byte(`{"labels":[{"name":"A","code":"A","message":"a"}]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GOPACKAGE", "")
+	if err := run([]string{"-in", in, "-out", out}, io.Discard); err == nil || !strings.Contains(err.Error(), "package name") {
+		t.Errorf("expected missing package error, got %v", err)
+	}
+	t.Setenv("GOPACKAGE", "fromenv")
+	if err := run([]string{"-in", in, "-out", out}, io.Discard); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if data, _ := os.ReadFile(out); !strings.Contains(string(data), "package fromenv\n") {
+		t.Errorf("expected package from $GOPACKAGE, got:\n%s", data)
+	}
+	if err := run([]string{"-in", in, "-out", out, "-package", "fromflag"}, io.Discard); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if data, _ := os.ReadFile(out); !strings.Contains(string(data), "package fromflag\n") {
+		t.Errorf("expected package from -package, got:\n%s", data)
+	}
+}
+
+func TestDecode_Invalid(t *testing.T) {
+	testCases := []struct {
+		name    string
+		catalog string
+		want    []string
+	}{
+		{"syntax", `{"labels": [}`, []string{"decoding catalog"}},
+		{"unknown field", `{"labels": [], "extra": 1}`, []string{`unknown field "extra"`}},
+		{"no labels", `{"package": "labels"}`, []string{"catalog has no labels"}},
+		{"invalid package", `{"package": "my-labels", "labels": [{"name": "A", "code": "A", "message": "a"}]}`, []string{`package "my-labels"`}},
+		{"reserved names", `{"labels": [
+			{"name": "Code", "code": "A", "message": "a"},
+			{"name": "Labels", "code": "B", "message": "b"}
+		]}`, []string{
+			`labels[0]: name "Code" is reserved by the generated code`,
+			`labels[1]: name "Labels" is reserved by the generated code`,
+		}},
+		{"invalid entries", `{"labels": [
+			{"name": "notExported", "code": "A", "message": "a"},
+			{"name": "B", "code": "A", "message": ""},
+			{"name": "B", "code": "", "message": "b", "http_status": 999, "grpc_code": 17}
+		]}`, []string{
+			`labels[0]: name "notExported" is not an exported identifier`,
+			`labels[1]: code "A" already used by labels[0]`,
+			`labels[1]: message is empty`,
+			`labels[2]: name "B" already used by labels[1]`,
+			`labels[2]: code is empty`,
+			`labels[2]: unknown http_status 999`,
+			`labels[2]: grpc_code 17 is out of range [0, 16]`,
+		}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := decode(strings.NewReader(tc.catalog))
+			if err == nil {
+				t.Fatalf("expected an error")
+			}
+			for _, want := range tc.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("expected %q in %q", want, err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+)
+
+const oopsPath = "github.com/piteego/oops"
+
+// Diagnostic is a problem reported at a position of the checked source.
+type Diagnostic struct {
+	Pos     token.Position
+	Message string
+}
+
+// checker reports misuses of the oops package in the files of a type-checked package.
+type checker struct {
+	fset  *token.FileSet
+	info  *types.Info
+	diags []Diagnostic
+	// commaOk holds the type assertions whose failure is checked, which cannot panic.
+	commaOk map[*ast.TypeAssertExpr]bool
+}
+
+func check(fset *token.FileSet, files []*ast.File, info *types.Info) []Diagnostic {
+	c := &checker{fset: fset, info: info, commaOk: make(map[*ast.TypeAssertExpr]bool)}
+	for _, file := range files {
+		ast.Inspect(file, c.collectCommaOk)
+	}
+	for _, file := range files {
+		ast.Inspect(file, c.visit)
+	}
+	return c.diags
+}
+
+func (c *checker) report(node ast.Node, msg string) {
+	c.diags = append(c.diags, Diagnostic{Pos: c.fset.Position(node.Pos()), Message: msg})
+}
+
+func (c *checker) collectCommaOk(node ast.Node) bool {
+	switch n := node.(type) {
+	case *ast.AssignStmt:
+		if len(n.Lhs) == 2 && len(n.Rhs) == 1 {
+			if assert, ok := ast.Unparen(n.Rhs[0]).(*ast.TypeAssertExpr); ok {
+				c.commaOk[assert] = true
+			}
+		}
+	case *ast.ValueSpec:
+		if len(n.Names) == 2 && len(n.Values) == 1 {
+			if assert, ok := ast.Unparen(n.Values[0]).(*ast.TypeAssertExpr); ok {
+				c.commaOk[assert] = true
+			}
+		}
+	}
+	return true
+}
+
+func (c *checker) visit(node ast.Node) bool {
+	switch n := node.(type) {
+	case *ast.CallExpr:
+		c.checkCall(n)
+	case *ast.ExprStmt:
+		if call, ok := ast.Unparen(n.X).(*ast.CallExpr); ok {
+			if name := c.oopsFunc(call); name == "Handle" || name == "HandleContext" || name == "Map.Handle" {
+				c.report(call, "result of oops."+name+" is not used; the handled error is lost")
+			}
+		}
+	case *ast.TypeAssertExpr:
+		if n.Type != nil && !c.commaOk[n] && c.isOopsErrorPtr(n.Type) && !c.isNewCall(n.X) {
+			c.report(n, "type assertion to *oops.Error may panic; use errors.As or the comma-ok form")
+		}
+	case *ast.CompositeLit:
+		if c.isOops(c.info.TypeOf(n), "Map") {
+			for _, elt := range n.Elts {
+				if kv, ok := elt.(*ast.KeyValueExpr); ok && !c.isNewAssertion(kv.Value) {
+					c.report(kv.Value, "oops.Map value is not created via oops.New")
+				}
+			}
+		}
+	}
+	return true
+}
+
+func (c *checker) checkCall(call *ast.CallExpr) {
+	switch c.oopsFunc(call) {
+	case "New", "NewCtx", "Newf":
+		if call.Ellipsis.IsValid() {
+			return // options are not known statically
+		}
+		first := 1
+		if c.oopsFunc(call) == "NewCtx" {
+			first = 2
+		}
+		for i := first; i < len(call.Args); i++ {
+			if arg, ok := ast.Unparen(call.Args[i]).(*ast.CallExpr); ok && c.oopsFunc(arg) == "Tag" {
+				return
+			}
+		}
+		c.report(call, "oops."+c.oopsFunc(call)+" called without oops.Tag; the error is labeled oops.Untagged")
+	case "Tag":
+		if len(call.Args) == 1 {
+			if tv, ok := c.info.Types[call.Args[0]]; ok && tv.IsNil() {
+				c.report(call, "oops.Tag(nil) has no effect; the error is labeled oops.Untagged")
+			}
+		}
+	}
+}
+
+// oopsFunc returns the name of the function or method of the oops package called, if any.
+func (c *checker) oopsFunc(call *ast.CallExpr) string {
+	var ident *ast.Ident
+	switch fun := ast.Unparen(call.Fun).(type) {
+	case *ast.Ident:
+		ident = fun
+	case *ast.SelectorExpr:
+		ident = fun.Sel
+	default:
+		return ""
+	}
+	fn, ok := c.info.Uses[ident].(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != oopsPath {
+		return ""
+	}
+	if recv := fn.Signature().Recv(); recv != nil {
+		if named, ok := types.Unalias(recv.Type()).(*types.Named); ok {
+			return named.Obj().Name() + "." + fn.Name()
+		}
+	}
+	return fn.Name()
+}
+
+func (c *checker) isNewCall(expr ast.Expr) bool {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	name := c.oopsFunc(call)
+	return name == "New" || name == "NewCtx" || name == "Newf"
+}
+
+// isNewAssertion reports whether expr is of the form oops.New(...).(*oops.Error).
+func (c *checker) isNewAssertion(expr ast.Expr) bool {
+	assert, ok := ast.Unparen(expr).(*ast.TypeAssertExpr)
+	return ok && c.isNewCall(assert.X)
+}
+
+func (c *checker) isOopsErrorPtr(expr ast.Expr) bool {
+	ptr, ok := c.info.TypeOf(expr).(*types.Pointer)
+	return ok && c.isOops(ptr.Elem(), "Error")
+}
+
+func (c *checker) isOops(t types.Type, name string) bool {
+	named, ok := types.Unalias(t).(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == oopsPath && named.Obj().Name() == name
+}
@@ -0,0 +1,156 @@
+// Command oopsvet reports misuses of the github.com/piteego/oops package:
+//
+//   - oops.New, oops.NewCtx and oops.Newf calls without oops.Tag, which silently fall back to oops.Untagged,
+//   - oops.Tag(nil) options,
+//   - single-value type assertions to *oops.Error that could panic,
+//     except on the result of oops.New which is always an *oops.Error,
+//   - oops.Map literals whose values are not created via oops.New,
+//   - results of oops.Handle, oops.HandleContext and Map.Handle that are not used.
+//
+// Usage:
+//
+//	oopsvet [directory ...]
+//
+// A directory ending with "/..." is checked recursively, skipping testdata and vendor directories.
+// Files are selected like the go command does, honoring build constraints for the current platform.
+// With no arguments, the current directory is checked. Diagnostics are printed as "file:line:col: message",
+// and the exit status is 1 if any is reported, or 2 if the source could not be loaded.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		args = []string{"."}
+	}
+	var dirs []string
+	for _, arg := range args {
+		found, err := expand(arg)
+		if err != nil {
+			fmt.Fprintf(stderr, "oopsvet: %v\n", err)
+			return 2
+		}
+		dirs = append(dirs, found...)
+	}
+	fset := token.NewFileSet()
+	imp := importer.ForCompiler(fset, "source", nil)
+	var diags []Diagnostic
+	for _, dir := range dirs {
+		found, err := checkDir(fset, imp, dir)
+		if err != nil {
+			fmt.Fprintf(stderr, "oopsvet: %v\n", err)
+			return 2
+		}
+		diags = append(diags, found...)
+	}
+	sort.SliceStable(diags, func(i, j int) bool {
+		a, b := diags[i].Pos, diags[j].Pos
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		return a.Offset < b.Offset
+	})
+	for _, d := range diags {
+		fmt.Fprintf(stdout, "%s: %s\n", d.Pos, d.Message)
+	}
+	if len(diags) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// expand returns the directories denoted by arg.
+func expand(arg string) ([]string, error) {
+	root, recursive := strings.CutSuffix(arg, "/...")
+	if !recursive {
+		return []string{arg}, nil
+	}
+	var dirs []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return err
+		}
+		if name := d.Name(); path != root && (name == "testdata" || name == "vendor" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+			return filepath.SkipDir
+		}
+		dirs = append(dirs, path)
+		return nil
+	})
+	return dirs, err
+}
+
+// checkDir type-checks the packages of a directory, the package with its internal test files and its external
+// test package separately, and checks their files. Files are selected by go/build, so that files excluded
+// by build constraints for the current platform are skipped, as the go command does.
+func checkDir(fset *token.FileSet, imp types.Importer, dir string) ([]Diagnostic, error) {
+	pkg, err := build.Default.ImportDir(dir, 0)
+	if err != nil {
+		var noGo *build.NoGoError
+		if errors.As(err, &noGo) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var diags []Diagnostic
+	for _, unit := range []struct {
+		name  string
+		files []string
+	}{
+		{pkg.Name, append(append([]string(nil), pkg.GoFiles...), pkg.TestGoFiles...)},
+		{pkg.Name + "_test", pkg.XTestGoFiles},
+	} {
+		if len(unit.files) == 0 {
+			continue
+		}
+		found, err := checkFiles(fset, imp, dir, unit.name, unit.files)
+		if err != nil {
+			return nil, err
+		}
+		diags = append(diags, found...)
+	}
+	return diags, nil
+}
+
+// checkFiles parses and type-checks the given files of dir as the named package, and checks them.
+func checkFiles(fset *token.FileSet, imp types.Importer, dir, name string, names []string) ([]Diagnostic, error) {
+	sort.Strings(names)
+	files := make([]*ast.File, 0, len(names))
+	for _, file := range names {
+		f, err := parser.ParseFile(fset, filepath.Join(dir, file), nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+	info := &types.Info{
+		Types: make(map[ast.Expr]types.TypeAndValue),
+		Uses:  make(map[*ast.Ident]types.Object),
+		Defs:  make(map[*ast.Ident]types.Object),
+	}
+	var typeErrs []error
+	conf := types.Config{Importer: imp, Error: func(err error) { typeErrs = append(typeErrs, err) }}
+	_, _ = conf.Check(name, fset, files, info)
+	if len(typeErrs) > 0 {
+		return nil, typeErrs[0]
+	}
+	return check(fset, files, info), nil
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var wantComment = regexp.MustCompile(`// want "((?:[^"\\]|\\.)*)"`)
+
+func TestRun(t *testing.T) {
+	dir := filepath.Join("testdata", "src", "bad")
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{dir}, &stdout, &stderr); code != 1 {
+		t.Fatalf("expected exit status 1, got %d: %s", code, stderr.String())
+	}
+	got := make(map[string]string)
+	for _, line := range strings.Split(strings.TrimSpace(stdout.String()), "\n") {
+		pos, msg, _ := strings.Cut(line, ": ")
+		file, rest, _ := strings.Cut(pos, ":")
+		lineNo, _, _ := strings.Cut(rest, ":")
+		got[filepath.Base(file)+":"+lineNo] = msg
+	}
+	want := wants(t, filepath.Join(dir, "bad.go"))
+	for at, pattern := range want {
+		if msg, ok := got[at]; !ok || !regexp.MustCompile(pattern).MatchString(msg) {
+			t.Errorf("%s: expected diagnostic matching %q, got %q", at, pattern, msg)
+		}
+	}
+	for at, msg := range got {
+		if _, ok := want[at]; !ok {
+			t.Errorf("%s: unexpected diagnostic %q", at, msg)
+		}
+	}
+}
+
+// wants returns the patterns of the "// want" comments of a file by "file:line".
+func wants(t *testing.T, path string) map[string]string {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	want := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		if m := wantComment.FindStringSubmatch(scanner.Text()); m != nil {
+			want[fmt.Sprintf("%s:%d", filepath.Base(path), line)] = strings.ReplaceAll(m[1], `\\`, `\`)
+		}
+	}
+	return want
+}
+
+func TestRun_BuildConstraints(t *testing.T) {
+	dir := filepath.Join("testdata", "src", "tagged")
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{dir}, &stdout, &stderr); code != 1 {
+		t.Fatalf("expected exit status 1, got %d: %s", code, stderr.String())
+	}
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) != 1 || !strings.Contains(lines[0], "tagged.go:6:") {
+		t.Errorf("expected the diagnostic of tagged.go only, got %q", stdout.String())
+	}
+}
+
+func TestRun_LoadError(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{filepath.Join("testdata", "missing")}, &stdout, &stderr); code != 2 {
+		t.Errorf("expected exit status 2, got %d", code)
+	}
+}
@@ -0,0 +1,53 @@
+package bad
+
+import (
+	"context"
+	"errors"
+
+	"github.com/piteego/oops"
+)
+
+var ErrNotFound oops.Label = errors.New("not found")
+
+var template = &oops.Error{}
+
+var errMap = oops.Map{
+	errors.New("a"): oops.New("a", oops.Tag(ErrNotFound)).(*oops.Error),
+	errors.New("b"): template, // want "oops.Map value is not created via oops.New"
+}
+
+func untagged() error {
+	return oops.New("untagged") // want "oops.New called without oops.Tag"
+}
+
+func untaggedf(id int) error {
+	_ = oops.Newf("user {id} not found", id, oops.Tag(ErrNotFound)).(*oops.Error)
+	return oops.Newf("user {id} not found", id) // want "oops.Newf called without oops.Tag"
+}
+
+func tagged(options ...oops.ErrorOption) error {
+	_ = oops.New("spread", options...)
+	_ = oops.NewCtx(context.Background(), "tagged", oops.Tag(ErrNotFound))
+	return oops.New("tagged", oops.With("key", 1), oops.Tag(ErrNotFound))
+}
+
+func tagNil() error {
+	return oops.New("nil label", oops.Tag(nil)) // want "oops.Tag\\(nil\\) has no effect"
+}
+
+func assertions(err error) {
+	_ = oops.New("safe", oops.Tag(ErrNotFound)).(*oops.Error)
+	_ = err.(*oops.Error) // want "type assertion to \\*oops.Error may panic"
+	oopsErr, ok := err.(*oops.Error)
+	_, _ = oopsErr, ok
+	switch err.(type) {
+	case *oops.Error:
+	}
+}
+
+func lost(err error) error {
+	oops.Handle(err)                              // want "result of oops.Handle is not used"
+	oops.HandleContext(context.Background(), err) // want "result of oops.HandleContext is not used"
+	errMap.Handle(err)                            // want "result of oops.Map.Handle is not used"
+	return oops.Handle(err, errMap.Handler())
+}
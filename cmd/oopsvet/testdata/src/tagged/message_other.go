@@ -0,0 +1,5 @@
+//go:build !unix
+
+package tagged
+
+func message() string { return "failed" }
@@ -0,0 +1,7 @@
+package tagged
+
+import "github.com/piteego/oops"
+
+func failed() error {
+	return oops.New(message()) // want "oops.New called without oops.Tag"
+}
@@ -0,0 +1,9 @@
+package tagged
+
+import "testing"
+
+func TestFailed(t *testing.T) {
+	if failed() == nil {
+		t.Fatal("expected an error")
+	}
+}
@@ -0,0 +1,68 @@
+package oops
+
+import "errors"
+
+// First returns a [Handler] returning the result of the first of the handlers returning a non-nil *[Error],
+// as [Handle] does, so that a list of handlers can be passed around as one.
+func First(handlers ...Handler) Handler {
+	return func(err error) *Error {
+		for i := range handlers {
+			if handlers[i] != nil {
+				if oopsErr := handlers[i](err); oopsErr != nil {
+					return oopsErr
+				}
+			}
+		}
+		return nil
+	}
+}
+
+// Chain returns a [Handler] invoking all the handlers in order, each one refining the result of the previous ones:
+// the first handler receives the error to handle, and the next ones receive the last non-nil *[Error] returned,
+// or the error to handle if there is none yet. A handler returning nil keeps the current result.
+//
+//	oops.Chain(example.ErrMap.Handler(), oops.OnlyLabel(example.NotFound.Error, addLookupHint))
+func Chain(handlers ...Handler) Handler {
+	return func(err error) *Error {
+		var result *Error
+		for i := range handlers {
+			if handlers[i] == nil {
+				continue
+			}
+			input := err
+			if result != nil {
+				input = result
+			}
+			if oopsErr := handlers[i](input); oopsErr != nil {
+				result = oopsErr
+			}
+		}
+		return result
+	}
+}
+
+// When returns a [Handler] invoking h only for the errors satisfying predicate, and returning nil otherwise.
+func When(predicate func(error) bool, h Handler) Handler {
+	return func(err error) *Error {
+		if h == nil || !predicate(err) {
+			return nil
+		}
+		return h(err)
+	}
+}
+
+// OnlyLabel returns a [Handler] invoking h only for the errors matching label using [errors.Is],
+// e.g. the results of the previous handlers of a [Chain].
+func OnlyLabel(label Label, h Handler) Handler {
+	return When(func(err error) bool { return errors.Is(err, label) }, h)
+}
+
+// Fallback returns a catch-all [Handler] returning a new *[Error] with the given message and [Label]
+// for any error, to be used last:
+//
+//	oops.Handle(err, example.ErrMap.Handler(), oops.Fallback(example.Internal.Error, "something went wrong"))
+func Fallback(label Label, msg string) Handler {
+	return func(error) *Error {
+		return New(msg, Tag(label)).(*Error)
+	}
+}
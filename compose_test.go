@@ -0,0 +1,98 @@
+package oops_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/piteego/oops"
+	"github.com/piteego/oops/example"
+)
+
+// withHint is a refining handler attaching a hint to the *oops.Error it receives.
+func withHint(hint string) oops.Handler {
+	return func(err error) *oops.Error {
+		var oopsErr *oops.Error
+		if !errors.As(err, &oopsErr) {
+			return nil
+		}
+		oops.With("hint", hint)(oopsErr)
+		return oopsErr
+	}
+}
+
+func TestFirst(t *testing.T) {
+	handler := oops.First(nil, example.ErrMap.Handler(), example.HandleRepoErr("user"))
+	if got := handler(example.RedisCacheMissed); got == nil || got.Error() != "cache key not found" {
+		t.Errorf("expected the map result, got %v", got)
+	}
+	if got := handler(errors.New("unknown")); got == nil || got.Label() != example.Internal.Error {
+		t.Errorf("expected the handler result, got %v", got)
+	}
+	if got := oops.First(example.ErrMap.Handler())(errors.New("unknown")); got != nil {
+		t.Errorf("expected nil, got %v", got)
+	}
+}
+
+func TestChain(t *testing.T) {
+	handler := oops.Chain(
+		example.ErrMap.Handler(),
+		nil,
+		oops.OnlyLabel(example.NotFound.Error, withHint("check the id")),
+		oops.OnlyLabel(example.Duplication.Error, withHint("use another name")),
+	)
+	err := oops.Handle(example.GormErrRecordNotFound, handler)
+	var oopsErr *oops.Error
+	if !errors.As(err, &oopsErr) || err.Error() != "entity not found" {
+		t.Fatalf("expected the map result, got %v", err)
+	}
+	if hint, _ := oopsErr.Attr("hint"); hint != "check the id" {
+		t.Errorf("expected the not found hint, got %v", hint)
+	}
+	if !errors.Is(err, example.GormErrRecordNotFound) {
+		t.Errorf("expected the original error as cause")
+	}
+	if got := handler(errors.New("unknown")); got != nil {
+		t.Errorf("expected nil, got %v", got)
+	}
+}
+
+func TestChain_refinesOriginalError(t *testing.T) {
+	var inputs []string
+	record := func(err error) *oops.Error {
+		inputs = append(inputs, err.Error())
+		return nil
+	}
+	handler := oops.Chain(record, example.HandleRepoErr("user"), record)
+	if got := handler(example.GormErrDuplicatedKey); got == nil || got.Error() != "duplicated user" {
+		t.Errorf("expected the handler result, got %v", got)
+	}
+	if got := strings.Join(inputs, ", "); got != "gorm duplicated key, duplicated user" {
+		t.Errorf("unexpected handler inputs: %s", got)
+	}
+}
+
+func TestWhen(t *testing.T) {
+	isRedis := func(err error) bool { return errors.Is(err, example.RedisCacheMissed) }
+	handler := oops.When(isRedis, example.HandleRepoErr("session"))
+	if got := handler(example.RedisCacheMissed); got == nil || got.Error() != "session not found" {
+		t.Errorf("expected the handler result, got %v", got)
+	}
+	if got := handler(example.GormErrRecordNotFound); got != nil {
+		t.Errorf("expected nil, got %v", got)
+	}
+	if got := oops.When(isRedis, nil)(example.RedisCacheMissed); got != nil {
+		t.Errorf("expected nil, got %v", got)
+	}
+}
+
+func TestFallback(t *testing.T) {
+	err := oops.Handle(errors.New("unknown"), example.ErrMap.Handler(), oops.Fallback(example.Internal.Error, "something went wrong"))
+	if !errors.Is(err, example.Internal.Error) || err.Error() != "something went wrong" {
+		t.Errorf("expected the fallback result, got %v", err)
+	}
+	handler := oops.Fallback(example.Internal.Error, "something went wrong")
+	if handler(errors.New("a")) == handler(errors.New("b")) {
+		t.Errorf("expected a new *oops.Error per call")
+	}
+}
@@ -0,0 +1,84 @@
+package oops
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	// Canceled label is tagged by [HandleContext] to errors caused by [context.Canceled].
+	Canceled Label = errors.New("canceled")
+	// DeadlineExceeded label is tagged by [HandleContext] to errors caused by [context.DeadlineExceeded].
+	DeadlineExceeded Label = errors.New("deadline exceeded")
+)
+
+// HandlerCtx is a context-aware [Handler]. It receives the request-scoped context
+// (e.g. tenant, locale or request ID) along with the error to handle.
+type HandlerCtx func(context.Context, error) *Error
+
+// Ctx adapts the [Handler] to a [HandlerCtx] that ignores the context.
+// It returns nil if the [Handler] is nil.
+func (h Handler) Ctx() HandlerCtx {
+	if h == nil {
+		return nil
+	}
+	return func(_ context.Context, err error) *Error { return h(err) }
+}
+
+// HandleContext processes an error using a list of context-aware handlers and returns an *[Error] as builtin error interface.
+// It behaves like [Handle], except that errors caused by [context.Canceled] and [context.DeadlineExceeded]
+// that no handler converts are tagged with the [Canceled] and [DeadlineExceeded] labels, so handlers may give
+// them labels of their own, e.g. for a query timeout; that the returned *[Error] is stamped with the correlation
+// identifiers active in ctx (see [FromContext]), and that the scoped hooks attached to ctx by [ContextWithHooks]
+// are invoked as well. Existing handlers and maps plug in via [Handler.Ctx] and [Map.Handler]:
+//
+//	oops.HandleContext(ctx, err, example.ErrMap.Handler().Ctx(), example.HandleRepoErr("user").Ctx())
+func HandleContext(ctx context.Context, err error, handlers ...HandlerCtx) error {
+	if err == nil {
+		return nil
+	}
+	switch err.(type) {
+	case *Error:
+		return err
+
+	default:
+		for i := range handlers {
+			if handlers[i] != nil {
+				if oopsErr := handlers[i](ctx, err); oopsErr != nil {
+					Because(err)(oopsErr)
+					oopsErr.handledBy(err, handlers[i])
+					return handledContext(ctx, err, oopsErr)
+				}
+			}
+		}
+		if oopsErr := handleContextErr(err); oopsErr != nil {
+			Because(err)(oopsErr)
+			oopsErr.handledBy(err, handleContextErr)
+			return handledContext(ctx, err, oopsErr)
+		}
+		return err
+	}
+}
+
+// handledContext stamps the result of [HandleContext] with the correlation identifiers active in ctx,
+// and invokes the global hooks and the scoped ones attached to ctx.
+func handledContext(ctx context.Context, original error, result *Error) *Error {
+	FromContext(ctx)(result)
+	globalHooks.handled(original, result)
+	if hooks := hooksFrom(ctx); hooks != nil {
+		hooks.handled(original, result)
+	}
+	return result
+}
+
+// handleContextErr is the [Handler] of the errors caused by [context.Canceled] and [context.DeadlineExceeded].
+func handleContextErr(err error) *Error {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return New("operation canceled", Tag(Canceled)).(*Error)
+	case errors.Is(err, context.DeadlineExceeded):
+		return New("operation deadline exceeded", Tag(DeadlineExceeded)).(*Error)
+	default:
+		return nil
+	}
+}
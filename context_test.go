@@ -0,0 +1,69 @@
+package oops_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/piteego/oops"
+	"github.com/piteego/oops/example"
+)
+
+type tenantKey struct{}
+
+func TestHandleContext(t *testing.T) {
+	ctx := context.WithValue(context.Background(), tenantKey{}, "acme")
+	var handleTenant oops.HandlerCtx = func(ctx context.Context, err error) *oops.Error {
+		tenant, _ := ctx.Value(tenantKey{}).(string)
+		return oops.New(tenant+": entity not found", oops.Tag(example.NotFound.Error)).(*oops.Error)
+	}
+	testCases := []struct {
+		name     string
+		err      error
+		handlers []oops.HandlerCtx
+		label    oops.Label
+		msg      string
+	}{
+		{"canceled", context.Canceled, nil, oops.Canceled, "operation canceled"},
+		{"wrapped deadline", fmt.Errorf("query: %w", context.DeadlineExceeded), []oops.HandlerCtx{example.ErrMap.Handler().Ctx()}, oops.DeadlineExceeded, "operation deadline exceeded"},
+		{"handled deadline", fmt.Errorf("query: %w", context.DeadlineExceeded), []oops.HandlerCtx{handleTenant}, example.NotFound.Error, "acme: entity not found"},
+		{"context-aware handler", example.GormErrRecordNotFound, []oops.HandlerCtx{nil, handleTenant}, example.NotFound.Error, "acme: entity not found"},
+		{"adapted handler", example.GormErrRecordNotFound, []oops.HandlerCtx{example.HandleRepoErr("user").Ctx()}, example.NotFound.Error, "user not found"},
+		{"adapted map", example.RedisCacheMissed, []oops.HandlerCtx{example.ErrMap.Handler().Ctx(), handleTenant}, example.NotFound.Error, "cache key not found"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := oops.HandleContext(ctx, tc.err, tc.handlers...)
+			if _, ok := got.(*oops.Error); !ok {
+				t.Fatalf("expected *oops.Error, got %T", got)
+			}
+			if got.Error() != tc.msg {
+				t.Errorf("expected %q, got %q", tc.msg, got.Error())
+			}
+			if !errors.Is(got, tc.label) {
+				t.Errorf("expected error labeled %q", tc.label)
+			}
+			if !errors.Is(got, tc.err) {
+				t.Errorf("expected original error to be a cause")
+			}
+		})
+	}
+	t.Run("nil error", func(t *testing.T) {
+		if got := oops.HandleContext(ctx, nil, handleTenant); got != nil {
+			t.Errorf("expected nil, got %v", got)
+		}
+	})
+	t.Run("unhandled error", func(t *testing.T) {
+		err := errors.New("unhandled error")
+		if got := oops.HandleContext(ctx, err, (oops.Handler)(nil).Ctx()); got != err {
+			t.Errorf("expected %v, got %v", err, got)
+		}
+	})
+	t.Run("already an oops error", func(t *testing.T) {
+		err := oops.New("already an oops error")
+		if got := oops.HandleContext(ctx, err, handleTenant); got != err {
+			t.Errorf("expected %v, got %v", err, got)
+		}
+	})
+}
@@ -0,0 +1,120 @@
+package oops
+
+import (
+	"context"
+	"sync"
+)
+
+// Correlation holds the identifiers used to correlate an *[Error] with logs and traces.
+type Correlation struct {
+	RequestID string
+	TraceID   string
+	SpanID    string
+}
+
+// Extractor reads correlation identifiers from a context, e.g. from a tracing library's span.
+// Empty fields are left for other extractors to fill.
+type Extractor func(context.Context) Correlation
+
+type (
+	requestIDKey   struct{}
+	traceParentKey struct{}
+)
+
+// ContextWithRequestID returns a copy of ctx carrying the given request ID.
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// ContextWithTraceParent returns a copy of ctx carrying the given [TraceParent].
+func ContextWithTraceParent(ctx context.Context, tp TraceParent) context.Context {
+	return context.WithValue(ctx, traceParentKey{}, tp)
+}
+
+var extractors = struct {
+	sync.RWMutex
+	list []*Extractor
+}{}
+
+// RegisterExtractor adds an [Extractor] consulted by [CorrelationFrom], after the request ID and
+// [TraceParent] stored by [ContextWithRequestID] and [ContextWithTraceParent] and the previously registered ones.
+// It is safe for concurrent use. The returned function unregisters the [Extractor].
+func RegisterExtractor(extract Extractor) (unregister func()) {
+	entry := &extract
+	extractors.Lock()
+	extractors.list = append(extractors.list, entry)
+	extractors.Unlock()
+	return func() {
+		extractors.Lock()
+		defer extractors.Unlock()
+		for i := range extractors.list {
+			if extractors.list[i] == entry {
+				extractors.list = append(extractors.list[:i:i], extractors.list[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// CorrelationFrom returns the correlation identifiers active in ctx.
+// For each identifier, the first non-empty value found is used.
+func CorrelationFrom(ctx context.Context) Correlation {
+	var c Correlation
+	if ctx == nil {
+		return c
+	}
+	c.RequestID, _ = ctx.Value(requestIDKey{}).(string)
+	if tp, ok := ctx.Value(traceParentKey{}).(TraceParent); ok {
+		c.TraceID, c.SpanID = tp.TraceID, tp.SpanID
+	}
+	// The extractors are called without holding the lock, so they may register or unregister extractors.
+	extractors.RLock()
+	list := extractors.list
+	extractors.RUnlock()
+	for _, extract := range list {
+		if c.RequestID != "" && c.TraceID != "" && c.SpanID != "" {
+			break
+		}
+		c.merge((*extract)(ctx))
+	}
+	return c
+}
+
+// merge fills the empty identifiers of c with the ones of other.
+func (c *Correlation) merge(other Correlation) {
+	if c.RequestID == "" {
+		c.RequestID = other.RequestID
+	}
+	if c.TraceID == "" {
+		c.TraceID = other.TraceID
+	}
+	if c.SpanID == "" {
+		c.SpanID = other.SpanID
+	}
+}
+
+// FromContext stamps the *[Error] with the correlation identifiers active in ctx. See [CorrelationFrom].
+func FromContext(ctx context.Context) ErrorOption {
+	return func(err *Error) {
+		err.correlation.merge(CorrelationFrom(ctx))
+	}
+}
+
+// NewCtx is like [New], but it stamps the *[Error] with the correlation identifiers active in ctx,
+// and also invokes the scoped hooks attached to ctx by [ContextWithHooks].
+func NewCtx(ctx context.Context, msg string, options ...ErrorOption) error {
+	err := newError(1, msg, append([]ErrorOption{FromContext(ctx)}, options...))
+	if hooks := hooksFrom(ctx); hooks != nil {
+		hooks.created(err)
+	}
+	return err
+}
+
+// RequestID returns the request ID active when the error was created, if any.
+func (err *Error) RequestID() string { return err.correlation.RequestID }
+
+// TraceID returns the trace ID active when the error was created, if any.
+func (err *Error) TraceID() string { return err.correlation.TraceID }
+
+// SpanID returns the span ID active when the error was created, if any.
+func (err *Error) SpanID() string { return err.correlation.SpanID }
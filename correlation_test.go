@@ -0,0 +1,132 @@
+package oops_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/piteego/oops"
+	"github.com/piteego/oops/example"
+)
+
+const testTraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func TestParseTraceParent(t *testing.T) {
+	testCases := []struct {
+		name    string
+		header  string
+		want    oops.TraceParent
+		wantErr bool
+	}{
+		{"valid", testTraceParent, oops.TraceParent{0, "4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7", 1}, false},
+		{"future version", "cc-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00-extra", oops.TraceParent{0xcc, "4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7", 0}, false},
+		{"empty", "", oops.TraceParent{}, true},
+		{"invalid version", "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", oops.TraceParent{}, true},
+		{"version 00 with extra", testTraceParent + "-extra", oops.TraceParent{}, true},
+		{"uppercase", "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01", oops.TraceParent{}, true},
+		{"zero trace id", "00-00000000000000000000000000000000-00f067aa0ba902b7-01", oops.TraceParent{}, true},
+		{"zero span id", "00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01", oops.TraceParent{}, true},
+		{"bad separator", "00_4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", oops.TraceParent{}, true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := oops.ParseTraceParent(tc.header)
+			if tc.wantErr {
+				if !errors.Is(err, oops.ErrInvalidTraceParent) {
+					t.Errorf("expected ErrInvalidTraceParent, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("expected %+v, got %+v", tc.want, got)
+			}
+		})
+	}
+	tp, _ := oops.ParseTraceParent(testTraceParent)
+	if tp.String() != testTraceParent || !tp.Sampled() {
+		t.Errorf("expected sampled %q, got %q", testTraceParent, tp)
+	}
+}
+
+type userKey struct{}
+
+func TestNewCtx(t *testing.T) {
+	tp, _ := oops.ParseTraceParent(testTraceParent)
+	ctx := oops.ContextWithTraceParent(oops.ContextWithRequestID(context.Background(), "req-1"), tp)
+	got := oops.NewCtx(ctx, "failed to process", oops.Tag(example.Internal.Error)).(*oops.Error)
+	if got.RequestID() != "req-1" || got.TraceID() != tp.TraceID || got.SpanID() != tp.SpanID {
+		t.Errorf("expected correlation identifiers to be stamped, got %q %q %q", got.RequestID(), got.TraceID(), got.SpanID())
+	}
+	if !errors.Is(got, example.Internal.Error) {
+		t.Errorf("expected error labeled %q", example.Internal.Error)
+	}
+	verbose := fmt.Sprintf("%+v", got)
+	for _, want := range []string{"request_id: req-1", "trace_id: " + tp.TraceID, "span_id: " + tp.SpanID} {
+		if !strings.Contains(verbose, want) {
+			t.Errorf("expected %q in %q", want, verbose)
+		}
+	}
+	data, err := json.Marshal(got)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(string(data), `"request_id":"req-1"`) {
+		t.Errorf("expected request_id in %s", data)
+	}
+}
+
+func TestRegisterExtractor(t *testing.T) {
+	unregister := oops.RegisterExtractor(func(ctx context.Context) oops.Correlation {
+		user, _ := ctx.Value(userKey{}).(string)
+		return oops.Correlation{RequestID: "from-extractor", TraceID: user}
+	})
+	ctx := oops.ContextWithRequestID(context.WithValue(context.Background(), userKey{}, "trace-of-user"), "req-1")
+	got := oops.New("failed", oops.FromContext(ctx)).(*oops.Error)
+	if got.RequestID() != "req-1" {
+		t.Errorf("expected context request ID to take precedence, got %q", got.RequestID())
+	}
+	if got.TraceID() != "trace-of-user" {
+		t.Errorf("expected extracted trace ID, got %q", got.TraceID())
+	}
+	unregister()
+	if c := oops.CorrelationFrom(ctx); c.TraceID != "" {
+		t.Errorf("expected unregistered extractor to be ignored, got %q", c.TraceID)
+	}
+}
+
+func TestRegisterExtractor_reentrant(t *testing.T) {
+	var unregister func()
+	unregister = oops.RegisterExtractor(func(context.Context) oops.Correlation {
+		unregister()
+		oops.RegisterExtractor(func(context.Context) oops.Correlation { return oops.Correlation{} })()
+		return oops.Correlation{TraceID: "once"}
+	})
+	done := make(chan oops.Correlation)
+	go func() { done <- oops.CorrelationFrom(context.Background()) }()
+	select {
+	case c := <-done:
+		if c.TraceID != "once" {
+			t.Errorf("expected the extracted trace ID, got %q", c.TraceID)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected an extractor to be able to register and unregister extractors")
+	}
+	if c := oops.CorrelationFrom(context.Background()); c.TraceID != "" {
+		t.Errorf("expected the extractor to have unregistered itself, got %q", c.TraceID)
+	}
+}
+
+func TestHandleContext_StampsCorrelation(t *testing.T) {
+	ctx := oops.ContextWithRequestID(context.Background(), "req-1")
+	got := oops.HandleContext(ctx, example.GormErrRecordNotFound, example.HandleRepoErr("user").Ctx())
+	if got.(*oops.Error).RequestID() != "req-1" {
+		t.Errorf("expected request ID to be stamped, got %q", got.(*oops.Error).RequestID())
+	}
+}
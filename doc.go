@@ -8,6 +8,10 @@
 // This allows you to classify application errors consistently.
 // Examples demonstrating how to define these custom categories can be found in the example package.
 //
+// - Label Registry: Use [Register] to describe labels with a stable code, a description and HTTP/gRPC statuses,
+// and [Lookup] or [LookupCode] to retrieve their [Definition]. The oopsgen command generates labels and their registration
+// from a JSON catalog.
+//
 // - Create Labeled Errors: Use the [New] function to create new errors and associate them
 // with your predefined labels. For instance:
 //
@@ -18,6 +22,11 @@
 // [ErrorOption] is a function that modifies an [Error] instance, allowing you to set options like
 // tagging the error with a [Label] or adding a stack trace with [Because].
 //
+// - Message Templates: Use [Newf] or [Bind] to keep dynamic values out of the message template,
+// so errors can be grouped, logged and localized using [Error.Template] and [Error.Params].
+//
+// - Attributes: Use [With] to attach key-value pairs, e.g. entity IDs, to an *[Error].
+//
 // - Stack Traces: Use [Because] in [New] function to append stack traces to your errors, providing valuable context for debugging.
 //
 // - Structured Error Handling:
@@ -30,6 +39,63 @@
 // Just define a map of errors to their corresponding *[Error] instances, and use the Map.Handle method to process errors.
 // The [Handle] method will append the original error to the stack of the returned *[Error].
 //
+// The returned *[Error] is a copy, so a Map can be shared across goroutines.
+//
 // -- Custom Handlers: Define complex [Handler] functions in different application layers.
 // The [Handle] function can then be used to process a given error by invoking a series of these custom handlers.
+//
+// -- Declarative Rules: The rules package maps errors to registered labels with rules loaded from a JSON file,
+// reloaded atomically without a redeploy and exposed as a [Handler].
+//
+// -- Composition: [First], [Chain], [When], [OnlyLabel] and [Fallback] combine handlers into a single [Handler],
+// e.g. to refine the result of a [Map] or to end a list of handlers with a catch-all.
+//
+// -- Adapters: Defer [HandleInto] to process the named return error of a function at a layer boundary,
+// or wrap calls with [Guard] and [Call], instead of handling the error after every call.
+//
+// -- Standard Library Handlers: [Stdlib] returns handlers classifying common errors of the standard library
+// (io, fs, context, net, url, sql, json and strconv) into predefined labels such as [NotFound] and [InvalidInput]:
+//
+//	oops.Handle(err, oops.Stdlib()...)
+//
+// [RegisterStdlib] registers these labels with codes such as "NOT_FOUND", unless the application uses them.
+// [Retryable] reports whether a classified error may succeed if retried. The sqlerr package wraps database/sql
+// to classify driver errors by their SQLSTATE code the same way, and the httperr package converts the non-2xx
+// responses of downstream services, including Problem Details bodies, into labeled errors.
+//
+// -- Context-aware Handlers: [HandlerCtx] functions receive the request-scoped context.
+// Use [HandleContext] to process errors with them; existing handlers and maps plug in through [Handler.Ctx] and [Map.Handler].
+//
+// - Builder: [B] returns an immutable [Builder] to construct errors fluently, e.g.
+// oops.B().Tag(label).Because(err).With("id", id).Msg("user not found").
+// Partial builders can be stored as package-level templates and shared across goroutines.
+//
+// - Correlation: Use [NewCtx] or the [FromContext] option to stamp errors with the request, trace and span IDs
+// active in a context. IDs are read from [ContextWithRequestID], [ContextWithTraceParent] and registered [Extractor] functions.
+//
+// - Formatting: The %+v verb prints the whole error tree, and *[Error] implements [json.Marshaler] and [slog.LogValuer].
+// Labels are encoded with their registry code, so an error decoded in another process by [Error.UnmarshalJSON]
+// still matches the local labels registered under the same code, see [RemoteLabel].
+// [Error.MarshalBinary] and [Error.UnmarshalBinary] provide a compact binary encoding for RPC and queues,
+// safe to decode from untrusted input.
+//
+// - Occurrences: Use [TrackOccurrences] to record when, on which host and in which process errors are created,
+// and the [Hop] of every handler converting them, for incident timelines. [Via] records a named layer,
+// and [SetClock] makes the times deterministic in tests.
+//
+// - Instance IDs: Use [EnsureID] at the edge of the application, or the [Identify] option, to give errors a unique,
+// sortable and URL-safe ID users can quote in support tickets. The ID is printed, logged and encoded in JSON,
+// and kept by [Handle] when the error is wrapped and converted again, see [Error.ID].
+//
+// - Observers: Use [OnNew] and [OnHandle] to observe every error created or handled, e.g. for metrics and audit logs.
+// Scoped [Hooks] are attached to a context using [ContextWithHooks].
+//
+// - Redaction: Use [RegisterRedactor] to hide sensitive data, such as emails or tokens, in every formatting,
+// JSON encoding and log output, and [Sensitive] to attach attributes whose values are never printed.
+// [Error.Unredacted] gives access to the original data for secure debugging.
+//
+// - Cause Tree Traversal: Use [Walk], [Flatten], [Root] and [Find] to inspect the tree of causes of an error.
+//
+// - Panic Recovery: Use [Recover] in a deferred call, or [Safe] to wrap a function,
+// to convert a panic into a labeled *[Error] holding the goroutine stack at the point of panic.
 package oops
@@ -2,6 +2,7 @@ package oops
 
 import (
 	"errors"
+	"runtime"
 )
 
 // Untagged label serves as a default for errors created with the [New] function
@@ -18,30 +19,102 @@ type Label error
 // You can use optional [ErrorOption] (e.g, [Because] to benefit stack trace,
 // or [Tag] a [Label] to categorize your application errors)
 func New(msg string, options ...ErrorOption) error {
+	return newError(1, msg, options)
+}
+
+// newError creates the *[Error] of [New], recording the call site skip frames above its caller.
+func newError(skip int, msg string, options []ErrorOption) *Error {
 	err := Error{msg: msg}
+	var pcs [1]uintptr
+	if runtime.Callers(skip+2, pcs[:]) > 0 {
+		err.pc = pcs[0]
+	}
 	for i := range options {
 		if options[i] != nil {
 			options[i](&err)
 		}
 	}
 	// If no label is set, use the default untagged label.
-	if err.Label == nil {
-		err.Label = Untagged
+	if err.label == nil {
+		err.label = Untagged
 	}
-	// append the label to the stack trace
-	Because(err.Label)(&err)
+	err.stamp()
+	globalHooks.created(&err)
 	return &err
 }
 
 // Error is a labeled error with stack trace implements the builtin error interface.
+// Its [Label] and its causes are kept separately, see [Error.Label] and [Error.Causes].
 type Error struct {
-	Label
-	msg   string
-	stack []error
+	id          string
+	label       Label
+	msg         string
+	stack       []error
+	frames      []Frame
+	correlation Correlation
+	attrs       []Attr
+	params      []Param
+	occurrence  Occurrence
+	hops        []Hop
+	pc          uintptr // call site of New
 }
 
-// Error implements golang's builtin error interface. It returns the client's message given in the [New] function.
-func (err *Error) Error() string { return err.msg }
+// clone returns a copy of the error, whose slices can be appended to without modifying the original.
+// The copy of an identified error gets its own instance ID.
+func (err *Error) clone() *Error {
+	c := *err
+	if c.id != "" {
+		c.id = NewID()
+	}
+	c.stack = c.stack[:len(c.stack):len(c.stack)]
+	c.attrs = append([]Attr(nil), c.attrs...)
+	c.params = append([]Param(nil), c.params...)
+	c.hops = c.hops[:len(c.hops):len(c.hops)]
+	return &c
+}
+
+// Error implements golang's builtin error interface. It returns the client's message given in the [New] function,
+// with the parameters bound to its template rendered (see [Newf]).
+func (err *Error) Error() string { return err.render() }
+
+// Label returns the [Label] tagged to the error, or [Untagged].
+func (err *Error) Label() Label { return err.label }
+
+// Causes returns a copy of the errors the error was caused by, as appended using [Because].
+// The [Label] is not a cause.
+func (err *Error) Causes() []error {
+	if len(err.stack) == 0 {
+		return nil
+	}
+	return append([]error(nil), err.stack...)
+}
 
-// Unwrap returns the wrapped errors, to allow interoperability with [errors.Is](), [errors.As]()
+// Unwrap returns the causes of the error, to allow interoperability with [errors.Is](), [errors.As]().
+// The [Label] is matched by the Is and As methods instead.
 func (err *Error) Unwrap() []error { return err.stack }
+
+// Is reports whether target is the [Label] of the error, so that [errors.Is] matches it.
+func (err *Error) Is(target error) bool {
+	return err.label != nil && errors.Is(err.label, target)
+}
+
+// As finds the first error in the [Label] chain that matches target, so that [errors.As] matches it.
+func (err *Error) As(target any) bool {
+	return err.label != nil && errors.As(err.label, target)
+}
+
+// Stack returns the causes of the error followed by its [Label].
+// It is the output of Unwrap before labels and causes were kept separately,
+// and is meant for code relying on it only.
+func (err *Error) Stack() []error {
+	stack := make([]error, 0, len(err.stack)+1)
+	stack = append(stack, err.stack...)
+	if err.label != nil {
+		stack = append(stack, err.label)
+	}
+	return stack
+}
+
+// Frames returns the goroutine stack captured for the error, e.g. at the point of a panic recovered by [Recover].
+// It returns nil if no stack was captured.
+func (err *Error) Frames() []Frame { return err.frames }
@@ -8,11 +8,11 @@ type ErrorOption func(*Error)
 // Tag sets a custom [Label] for the *[Error]. If the error already has a non-nil [Label], it will not overwrite it.
 func Tag(custom Label) ErrorOption {
 	return func(err *Error) {
-		if err.Label != nil {
+		if err.label != nil {
 			// Do not overwrite existing label
 			return
 		}
-		err.Label = custom
+		err.label = custom
 	}
 }
 
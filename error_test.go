@@ -127,3 +127,46 @@ func TestError_Unwrap(t *testing.T) {
 	}
 	t.Logf("%+q", oopsErr.Unwrap())
 }
+
+type codedLabel struct{ code int }
+
+func (l *codedLabel) Error() string { return "coded label " + fmt.Sprint(l.code) }
+
+func TestError_LabelAndCauses(t *testing.T) {
+	label := &codedLabel{code: 42}
+	cause := errors.New("cause error")
+	inner := oops.New("inner", oops.Tag(example.NotFound.Error))
+	got := oops.New("outer", oops.Tag(label), oops.Because(cause, inner)).(*oops.Error)
+	if got.Label() != label {
+		t.Errorf("expected label %v, got %v", label, got.Label())
+	}
+	causes := got.Causes()
+	if len(causes) != 2 || causes[0] != cause || causes[1] != inner {
+		t.Errorf("expected causes without the label, got %q", causes)
+	}
+	causes[0] = nil
+	if got.Causes()[0] != cause {
+		t.Errorf("expected Causes to return a copy")
+	}
+	if unwrapped := got.Unwrap(); len(unwrapped) != 2 {
+		t.Errorf("expected Unwrap to return causes only, got %q", unwrapped)
+	}
+	if stack := got.Stack(); len(stack) != 3 || stack[2] != label {
+		t.Errorf("expected Stack to end with the label, got %q", stack)
+	}
+	for _, target := range []error{label, cause, inner, example.NotFound.Error} {
+		if !errors.Is(got, target) {
+			t.Errorf("expected errors.Is(got, %q) to be true", target)
+		}
+	}
+	var coded *codedLabel
+	if !errors.As(got, &coded) || coded.code != 42 {
+		t.Errorf("expected errors.As to find the label, got %v", coded)
+	}
+	if errors.Is(got, example.Internal.Error) {
+		t.Errorf("expected errors.Is with another label to be false")
+	}
+	if untagged := oops.New("untagged").(*oops.Error); untagged.Label() != oops.Untagged || untagged.Causes() != nil {
+		t.Errorf("expected untagged error without causes, got %v %q", untagged.Label(), untagged.Causes())
+	}
+}
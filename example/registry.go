@@ -0,0 +1,33 @@
+package example
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/piteego/oops"
+)
+
+func init() {
+	oops.Register(
+		Unimplemented.definition(http.StatusNotImplemented, 12),
+		Internal.definition(http.StatusInternalServerError, 13),
+		Unauthorized.definition(http.StatusUnauthorized, 16),
+		Forbidden.definition(http.StatusForbidden, 7),
+		Unprocessable.definition(http.StatusUnprocessableEntity, 9),
+		Validation.definition(http.StatusBadRequest, 3),
+		NotFound.definition(http.StatusNotFound, 5),
+		Duplication.definition(http.StatusConflict, 6),
+	)
+	oops.RegisterMap(ErrMap)
+}
+
+// definition describes the custom label for the oops registry, using its Code as registry code.
+func (c custom) definition(httpStatus, grpcCode int) oops.Definition {
+	return oops.Definition{
+		Label:       c.Error,
+		Code:        strconv.Itoa(c.Code),
+		Description: c.Description,
+		HTTPStatus:  httpStatus,
+		GRPCCode:    grpcCode,
+	}
+}
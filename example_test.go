@@ -1,11 +1,16 @@
 package oops_test
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/piteego/oops"
 	"github.com/piteego/oops/example"
+	"io/fs"
+	"os"
 	"strconv"
+	"time"
 )
 
 func ExampleLabel() {
@@ -59,14 +64,14 @@ func ExampleNew_tagCustomLabel() {
 		var oopsErr *oops.Error
 		fmt.Println(errors.As(err, &oopsErr))
 		if oopsErr != nil {
-			fmt.Printf("%q", oopsErr.Unwrap())
+			fmt.Printf("%q", oopsErr.Label())
 		}
 	}
 	// Output:
 	// emit macho dwarf: elf header corrupted
 	// true
 	// true
-	// ["custom error label"]
+	// "custom error label"
 }
 
 func ExampleNew_causedByStackErrors() {
@@ -114,7 +119,25 @@ func ExampleError_Unwrap() {
 		}
 	}
 	// Output:
-	// ["cause error 1" "cause error 2" "custom error label"]
+	// ["cause error 1" "cause error 2"]
+}
+
+func ExampleError_Stack() {
+	customLabel := oops.Label(errors.New("custom error label"))
+	err := oops.New("emit macho dwarf: elf header corrupted",
+		oops.Tag(customLabel),
+		oops.Because(errors.New("cause error 1")),
+	)
+	var oopsErr *oops.Error
+	if errors.As(err, &oopsErr) {
+		fmt.Printf("%q\n", oopsErr.Label())
+		fmt.Printf("%q\n", oopsErr.Causes())
+		fmt.Printf("%q\n", oopsErr.Stack())
+	}
+	// Output:
+	// "custom error label"
+	// ["cause error 1"]
+	// ["cause error 1" "custom error label"]
 }
 
 func ExampleTag() {
@@ -151,7 +174,7 @@ func ExampleBecause() {
 		}
 	}
 	// Output:
-	// ["cause error 1" "cause error 2" "untagged"]
+	// ["cause error 1" "cause error 2"]
 	// true
 	// true
 }
@@ -195,7 +218,7 @@ func ExampleHandler_asVariable() {
 	fmt.Printf("%q\n", oopsErr.Unwrap())
 	// Output:
 	// handled error.1
-	// ["error.1" "forbidden access"]
+	// ["error.1"]
 }
 
 func ExampleHandle() {
@@ -229,3 +252,183 @@ func ExampleHandle() {
 	// an error with nil handler
 	// already an oops error
 }
+
+func ExampleRecover() {
+	process := func() (err error) {
+		defer oops.Recover(&err, oops.Tag(example.Internal.Error))
+		panic("unexpected state")
+	}
+	err := process()
+	fmt.Println(err)
+	fmt.Println(errors.Is(err, example.Internal.Error))
+	// Output:
+	// panic: unexpected state
+	// true
+}
+
+func ExampleSafe() {
+	err := oops.Safe(func() error {
+		panic(strconv.ErrRange)
+	})
+	fmt.Println(err)
+	fmt.Println(errors.Is(err, oops.Panicked))
+	fmt.Println(errors.Is(err, strconv.ErrRange))
+	// Output:
+	// panic: value out of range
+	// true
+	// true
+}
+
+func ExampleHandleContext() {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := oops.HandleContext(ctx, ctx.Err(), example.ErrMap.Handler().Ctx())
+	fmt.Println(err)
+	fmt.Println(errors.Is(err, oops.Canceled))
+	fmt.Println(oops.HandleContext(ctx, example.GormErrRecordNotFound, example.HandleRepoErr("user").Ctx()))
+	// Output:
+	// operation canceled
+	// true
+	// user not found
+}
+
+func ExampleNewCtx() {
+	tp, _ := oops.ParseTraceParent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	ctx := oops.ContextWithTraceParent(context.Background(), tp)
+	ctx = oops.ContextWithRequestID(ctx, "req-42")
+	err := oops.NewCtx(ctx, "failed to process", oops.Tag(example.Internal.Error))
+	fmt.Printf("%+v\n", err)
+	// Output:
+	// failed to process
+	//     label: something went wrong
+	//     request_id: req-42
+	//     trace_id: 4bf92f3577b34da6a3ce929d0e0e4736
+	//     span_id: 00f067aa0ba902b7
+}
+
+func ExampleNewf() {
+	err := oops.Newf("user {id} not found in {table}", 42, "users", oops.Tag(example.NotFound.Error))
+	fmt.Println(err)
+	var oopsErr *oops.Error
+	if errors.As(err, &oopsErr) {
+		fmt.Println(oopsErr.Template())
+		fmt.Println(oopsErr.Params())
+	}
+	// Output:
+	// user 42 not found in users
+	// user {id} not found in {table}
+	// [{id 42} {table users}]
+}
+
+func ExampleRegisterRedactor() {
+	unregister := oops.RegisterRedactor(oops.RedactEmails)
+	defer unregister()
+	err := oops.New("user not found",
+		oops.Tag(example.NotFound.Error),
+		oops.Sensitive("password", "hunter2"),
+		oops.Because(errors.New("no user with email john@example.com")),
+	)
+	fmt.Printf("%+v\n", err)
+	// Output:
+	// user not found
+	//     label: resource not found
+	//     attrs:
+	//         password: [REDACTED]
+	//     causes:
+	//         - no user with email [REDACTED]
+}
+
+func ExampleB() {
+	notFound := oops.B().Tag(example.NotFound.Error)
+	err := notFound.Because(example.RedisCacheMissed).With("key", "user:42").Msg("user not found")
+	fmt.Printf("%+v\n", err)
+	// Output:
+	// user not found
+	//     label: resource not found
+	//     attrs:
+	//         key: user:42
+	//     causes:
+	//         - redis cache missed
+}
+
+func ExampleStdlib() {
+	_, err := os.Open("/does/not/exist")
+	err = oops.Handle(err, oops.Stdlib()...)
+	fmt.Println(err)
+	fmt.Println(errors.Is(err, oops.NotFound), errors.Is(err, fs.ErrNotExist))
+	// Output:
+	// file does not exist
+	// true true
+}
+
+func ExampleError_UnmarshalJSON() {
+	data, _ := json.Marshal(oops.New("user not found", oops.Tag(example.NotFound.Error)))
+	fmt.Println(string(data))
+	// In another process registering the same codes:
+	var decoded oops.Error
+	_ = json.Unmarshal(data, &decoded)
+	fmt.Println(errors.Is(&decoded, example.NotFound.Error))
+	// Output:
+	// {"message":"user not found","label":"resource not found","code":"30"}
+	// true
+}
+
+func ExampleHandleInto() {
+	findUser := func(id int) (name string, err error) {
+		defer oops.HandleInto(&err, example.HandleRepoErr("user"))
+		return "", example.GormErrRecordNotFound
+	}
+	_, err := findUser(42)
+	fmt.Println(err, errors.Is(err, example.NotFound.Error))
+	// Output:
+	// user not found true
+}
+
+func ExampleChain() {
+	hint := func(err error) *oops.Error {
+		var oopsErr *oops.Error
+		if errors.As(err, &oopsErr) {
+			oops.With("hint", "check the id")(oopsErr)
+		}
+		return oopsErr
+	}
+	handler := oops.First(
+		oops.Chain(example.ErrMap.Handler(), oops.OnlyLabel(example.NotFound.Error, hint)),
+		oops.Fallback(example.Internal.Error, "something went wrong"),
+	)
+	fmt.Printf("%+v\n", oops.Handle(example.RedisCacheMissed, handler))
+	fmt.Println(oops.Handle(errors.New("unknown"), handler))
+	// Output:
+	// cache key not found
+	//     label: resource not found
+	//     attrs:
+	//         hint: check the id
+	//     causes:
+	//         - redis cache missed
+	// something went wrong
+}
+
+func ExampleTrackOccurrences() {
+	defer oops.SetClock(func() time.Time { return time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC) })()
+	defer oops.TrackOccurrences()()
+	repoErr := oops.Handle(example.GormErrRecordNotFound, example.HandleRepoErr("user"))
+	err := oops.Handle(fmt.Errorf("get profile: %w", repoErr), oops.Fallback(example.Internal.Error, "profile unavailable"))
+	fmt.Println(err.(*oops.Error).Occurrence().Time)
+	for _, hop := range err.(*oops.Error).Hops() {
+		fmt.Println(hop)
+	}
+	// Output:
+	// 2026-01-02 03:04:05 +0000 UTC
+	// example.HandleRepoErr at 2026-01-02T03:04:05Z
+	// oops.Fallback at 2026-01-02T03:04:05Z
+}
+
+func ExampleEnsureID() {
+	repoErr := oops.Handle(example.GormErrRecordNotFound, example.HandleRepoErr("user"))
+	err := oops.Handle(fmt.Errorf("get profile: %w", repoErr), oops.Fallback(example.Internal.Error, "profile unavailable"))
+	// At the edge, e.g. in the HTTP handler writing the response body.
+	err, id := oops.EnsureID(err)
+	fmt.Println(len(id), id == err.(*oops.Error).ID())
+	// Output:
+	// 26 true
+}
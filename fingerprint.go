@@ -0,0 +1,82 @@
+package oops
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"runtime"
+)
+
+// FingerprintVersion is the version of the algorithm used by [Error.Fingerprint].
+// It changes whenever the algorithm changes, so that stored fingerprints can be invalidated.
+const FingerprintVersion = 1
+
+// Fingerprint returns a stable identifier of the error kind, to group similar failures
+// regardless of the dynamic data in their messages.
+//
+// The fingerprint is the hex encoded first 16 bytes of the SHA-256 digest of the following lines:
+//
+//	oops-fingerprint-v<FingerprintVersion>
+//	label:<label message>
+//	template:<message template with quoted strings, UUIDs, decimal and hex numbers replaced by placeholders>
+//	site:<package qualified function>:<line>
+//	cause:<cause fingerprint>
+//
+// where the message template is the one of [Error.Template], so parameters bound using [Newf] or [Bind]
+// never alter the fingerprint, and there is one cause line per cause, in order. The fingerprint of an *[Error] cause is its own Fingerprint,
+// and the fingerprint of any other cause is its dynamic type only, e.g. "*pq.Error": their messages are free texts,
+// such as the values of a driver error appended as a cause by [Handle], which would split a kind of failure.
+// The site is where [New] was called, or where the panic happened for errors created by [Recover].
+// File paths are left out, so the fingerprint is stable across process restarts and builds as long as line numbers do not change.
+func (err *Error) Fingerprint() string {
+	return err.fingerprint(0)
+}
+
+func (err *Error) fingerprint(depth int) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "oops-fingerprint-v%d\n", FingerprintVersion)
+	fmt.Fprintf(h, "label:%s\n", labelText(err.label))
+	fmt.Fprintf(h, "template:%s\n", normalize(err.msg))
+	if site, ok := err.site(); ok {
+		fmt.Fprintf(h, "site:%s:%d\n", site.Function, site.Line)
+	}
+	for _, cause := range err.stack {
+		if oopsErr, ok := cause.(*Error); ok && depth < maxDepth {
+			fmt.Fprintf(h, "cause:%s\n", oopsErr.fingerprint(depth+1))
+			continue
+		}
+		fmt.Fprintf(h, "cause:%T\n", cause)
+	}
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
+
+// site returns the frame where the error originated.
+func (err *Error) site() (Frame, bool) {
+	if len(err.frames) > 0 {
+		return err.frames[0], true
+	}
+	if err.pc == 0 {
+		return Frame{}, false
+	}
+	frame, _ := runtime.CallersFrames([]uintptr{err.pc}).Next()
+	return Frame{Function: frame.Function, File: frame.File, Line: frame.Line}, true
+}
+
+var parameters = []struct {
+	pattern     *regexp.Regexp
+	placeholder string
+}{
+	{regexp.MustCompile(`"(?:[^"\\]|\\.)*"|'(?:[^'\\]|\\.)*'`), "<str>"},
+	{regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`), "<uuid>"},
+	{regexp.MustCompile(`\b\d+(?:\.\d+)?\b`), "<num>"},
+	{regexp.MustCompile(`(?i)\b0x[0-9a-f]+\b|\b[0-9a-f]{8,}\b`), "<hex>"},
+}
+
+// normalize strips the parameters out of an error message, see [Error.Fingerprint].
+func normalize(msg string) string {
+	for _, p := range parameters {
+		msg = p.pattern.ReplaceAllLiteralString(msg, p.placeholder)
+	}
+	return msg
+}
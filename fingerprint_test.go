@@ -0,0 +1,78 @@
+package oops_test
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"testing"
+
+	"github.com/piteego/oops"
+	"github.com/piteego/oops/example"
+)
+
+func newUserNotFound(msg string, label oops.Label, causes ...error) *oops.Error {
+	return oops.New(msg, oops.Tag(label), oops.Because(causes...)).(*oops.Error)
+}
+
+func TestError_Fingerprint(t *testing.T) {
+	base := newUserNotFound(`user 42 not found`, example.NotFound.Error, fmt.Errorf("query %q failed", "abc"))
+	testCases := []struct {
+		name string
+		err  *oops.Error
+		same bool
+	}{
+		{"same kind", newUserNotFound(`user 42 not found`, example.NotFound.Error, fmt.Errorf("query %q failed", "abc")), true},
+		{"other number", newUserNotFound(`user 1337 not found`, example.NotFound.Error, fmt.Errorf("query %q failed", "xyz")), true},
+		{"uuid", newUserNotFound(`user 123e4567-e89b-12d3-a456-426614174000 not found`, example.NotFound.Error, fmt.Errorf("query %q failed", "")), false},
+		{"other label", newUserNotFound(`user 42 not found`, example.Internal.Error, fmt.Errorf("query %q failed", "abc")), false},
+		{"other cause message", newUserNotFound(`user 42 not found`, example.NotFound.Error, errors.New("timeout")), true},
+		{"other cause type", newUserNotFound(`user 42 not found`, example.NotFound.Error, &fs.PathError{Op: "open", Path: "users", Err: fs.ErrNotExist}), false},
+		{"other site", oops.New(`user 42 not found`, oops.Tag(example.NotFound.Error), oops.Because(fmt.Errorf("query %q failed", "abc"))).(*oops.Error), false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.err.Fingerprint()
+			if len(got) != 32 {
+				t.Errorf("expected 32 hex characters, got %q", got)
+			}
+			if (got == base.Fingerprint()) != tc.same {
+				t.Errorf("expected same fingerprint to be %t, got %q and %q", tc.same, base.Fingerprint(), got)
+			}
+		})
+	}
+	t.Run("uuids are parameters", func(t *testing.T) {
+		a := newUserNotFound(`user 123e4567-e89b-12d3-a456-426614174000 not found`, example.NotFound.Error)
+		b := newUserNotFound(`user 00000000-0000-0000-0000-0000000000ff not found`, example.NotFound.Error)
+		if a.Fingerprint() != b.Fingerprint() {
+			t.Errorf("expected same fingerprint, got %q and %q", a.Fingerprint(), b.Fingerprint())
+		}
+	})
+	t.Run("nested causes", func(t *testing.T) {
+		wrap := func(cause error) *oops.Error {
+			return oops.New("failed to process", oops.Because(cause)).(*oops.Error)
+		}
+		a := wrap(newUserNotFound(`user 1 not found`, example.NotFound.Error))
+		b := wrap(newUserNotFound(`user 2 not found`, example.NotFound.Error))
+		c := wrap(newUserNotFound(`user 2 not found`, example.Internal.Error))
+		if a.Fingerprint() != b.Fingerprint() || a.Fingerprint() == c.Fingerprint() {
+			t.Errorf("expected nested cause fingerprints to be used, got %q, %q and %q", a.Fingerprint(), b.Fingerprint(), c.Fingerprint())
+		}
+	})
+	t.Run("handled causes", func(t *testing.T) {
+		handle := func(detail string) *oops.Error {
+			cause := fmt.Errorf(`duplicate key value violates unique constraint "users_email_key": Key (email)=(%s) already exists`, detail)
+			return oops.Handle(cause, oops.Fallback(example.Duplication.Error, "duplicated user")).(*oops.Error)
+		}
+		if a, b := handle("alice@x.com"), handle("bob@y.org"); a.Fingerprint() != b.Fingerprint() {
+			t.Errorf("expected the texts of the causes not to alter the fingerprint, got %q and %q", a.Fingerprint(), b.Fingerprint())
+		}
+	})
+	t.Run("panics", func(t *testing.T) {
+		recovered := func(v any) *oops.Error {
+			return oops.Safe(func() error { panic(v) }).(*oops.Error)
+		}
+		if recovered(1).Fingerprint() != recovered(2).Fingerprint() {
+			t.Errorf("expected panics at the same site to share a fingerprint")
+		}
+	})
+}
@@ -0,0 +1,276 @@
+package oops
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxDepth bounds the nesting of *[Error] causes rendered by formatting and serialization.
+const maxDepth = 32
+
+// Format implements [fmt.Formatter]. The %v and %s verbs print the client's message given in the [New] function,
+// and %q prints it quoted. The %+v verb prints the whole error tree: the message, the [Label],
+// the instance ID, the message template and its parameters, the correlation identifiers, the occurrence and the hops when tracked
+// (see [TrackOccurrences]), the attributes, the causes (nested *[Error] causes are printed the same way)
+// and the captured frames.
+// The output is redacted, see [RegisterRedactor] and [Error.Unredacted].
+func (err *Error) Format(s fmt.State, verb rune) {
+	redacting.format(s, verb, err)
+}
+
+// MarshalJSON implements [json.Marshaler]. It encodes the whole error tree as printed by the %+v verb,
+// e.g. as a response body carrying the instance ID users can quote (see [EnsureID]),
+// along with the registry code of the labels (see [Register]), so that [Error.UnmarshalJSON] restores them.
+// Parameter and attribute values that [encoding/json] cannot encode, such as a NaN float, are encoded as texts.
+func (err *Error) MarshalJSON() ([]byte, error) {
+	return json.Marshal(redacting.toJSON(err, 0))
+}
+
+// LogValue implements [slog.LogValuer], so the *[Error] is logged as a group of its message,
+// instance ID, [Label], message template and parameters, correlation identifiers, occurrence, hops, attributes and causes.
+func (err *Error) LogValue() slog.Value {
+	return redacting.logValue(err)
+}
+
+// renderer renders an *[Error] for formatting, serialization and logging, redacting it or not.
+type renderer struct {
+	redact bool
+}
+
+var redacting = renderer{redact: true}
+
+// text returns s redacted by the registered redactors.
+func (r renderer) text(s string) string {
+	if !r.redact {
+		return s
+	}
+	return redact(s)
+}
+
+// value returns v as it should be rendered: booleans, numbers and times are kept as they are,
+// any other value is rendered as a redacted text, e.g. the credentials of a *url.URL or the email of a struct.
+func (r renderer) value(v any) any {
+	switch v := v.(type) {
+	case nil, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, uintptr, float32, float64, time.Time:
+		return v
+	case string:
+		return r.text(v)
+	case error:
+		return r.text(v.Error())
+	default:
+		return r.text(fmt.Sprint(v))
+	}
+}
+
+// attr returns the value of the attribute as it should be rendered.
+func (r renderer) attr(attr Attr) any {
+	if attr.Sensitive && r.redact {
+		return Redacted
+	}
+	return r.value(attr.Value)
+}
+
+func (r renderer) format(s fmt.State, verb rune, err *Error) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			r.writeVerbose(s, err, "", 0)
+			return
+		}
+		io.WriteString(s, r.text(err.Error()))
+	case 's':
+		io.WriteString(s, r.text(err.Error()))
+	case 'q':
+		fmt.Fprintf(s, "%q", r.text(err.Error()))
+	default:
+		fmt.Fprintf(s, "%%!%c(*oops.Error=%s)", verb, r.text(err.Error()))
+	}
+}
+
+func (r renderer) writeVerbose(w io.Writer, err *Error, indent string, depth int) {
+	io.WriteString(w, r.text(err.Error()))
+	field := func(name, value string) {
+		if value != "" {
+			fmt.Fprintf(w, "\n%s    %s: %s", indent, name, value)
+		}
+	}
+	field("id", err.id)
+	field("label", labelText(err.label))
+	if len(err.params) > 0 {
+		field("template", r.text(err.msg))
+		fmt.Fprintf(w, "\n%s    params:", indent)
+		for _, param := range err.params {
+			fmt.Fprintf(w, "\n%s        %s: %v", indent, param.Name, r.value(param.Value))
+		}
+	}
+	field("request_id", err.correlation.RequestID)
+	field("trace_id", err.correlation.TraceID)
+	field("span_id", err.correlation.SpanID)
+	if !err.occurrence.Time.IsZero() {
+		field("time", err.occurrence.Time.Format(time.RFC3339Nano))
+	}
+	field("host", err.occurrence.Host)
+	if err.occurrence.PID != 0 {
+		field("pid", strconv.Itoa(err.occurrence.PID))
+	}
+	if len(err.hops) > 0 {
+		fmt.Fprintf(w, "\n%s    hops:", indent)
+		for _, hop := range err.hops {
+			fmt.Fprintf(w, "\n%s        - %s", indent, hop)
+		}
+	}
+	if len(err.attrs) > 0 {
+		fmt.Fprintf(w, "\n%s    attrs:", indent)
+		for _, attr := range err.attrs {
+			fmt.Fprintf(w, "\n%s        %s: %v", indent, attr.Key, r.attr(attr))
+		}
+	}
+	if causes := err.stack; len(causes) > 0 {
+		fmt.Fprintf(w, "\n%s    causes:", indent)
+		for _, cause := range causes {
+			fmt.Fprintf(w, "\n%s        - ", indent)
+			if oopsErr, ok := cause.(*Error); ok && depth < maxDepth {
+				r.writeVerbose(w, oopsErr, indent+"          ", depth+1)
+				continue
+			}
+			io.WriteString(w, r.text(cause.Error()))
+		}
+	}
+	if len(err.frames) > 0 {
+		fmt.Fprintf(w, "\n%s    frames:", indent)
+		for _, frame := range err.frames {
+			fmt.Fprintf(w, "\n%s        - %s", indent, frame)
+		}
+	}
+}
+
+func labelText(label Label) string {
+	if label == nil {
+		return ""
+	}
+	return label.Error()
+}
+
+// errorJSON is the JSON representation of an *[Error]. Causes that are not an *[Error] only have a message.
+type errorJSON struct {
+	Message   string         `json:"message"`
+	ID        string         `json:"id,omitempty"`
+	Label     string         `json:"label,omitempty"`
+	Code      string         `json:"code,omitempty"`
+	Template  string         `json:"template,omitempty"`
+	Params    map[string]any `json:"params,omitempty"`
+	RequestID string         `json:"request_id,omitempty"`
+	TraceID   string         `json:"trace_id,omitempty"`
+	SpanID    string         `json:"span_id,omitempty"`
+	Time      time.Time      `json:"time,omitzero"`
+	Host      string         `json:"host,omitempty"`
+	PID       int            `json:"pid,omitempty"`
+	Hops      []Hop          `json:"hops,omitempty"`
+	Attrs     map[string]any `json:"attrs,omitempty"`
+	Causes    []*errorJSON   `json:"causes,omitempty"`
+	Frames    []Frame        `json:"frames,omitempty"`
+}
+
+func (r renderer) toJSON(err *Error, depth int) *errorJSON {
+	v := &errorJSON{
+		Message:   r.text(err.Error()),
+		ID:        err.id,
+		Label:     labelText(err.label),
+		Code:      labelCode(err.label),
+		RequestID: err.correlation.RequestID,
+		TraceID:   err.correlation.TraceID,
+		SpanID:    err.correlation.SpanID,
+		Time:      err.occurrence.Time,
+		Host:      err.occurrence.Host,
+		PID:       err.occurrence.PID,
+		Hops:      err.hops,
+		Frames:    err.frames,
+	}
+	if len(err.params) > 0 {
+		v.Template = r.text(err.msg)
+		v.Params = make(map[string]any, len(err.params))
+		for _, param := range err.params {
+			v.Params[param.Name] = jsonValue(r.value(param.Value))
+		}
+	}
+	if len(err.attrs) > 0 {
+		v.Attrs = make(map[string]any, len(err.attrs))
+		for _, attr := range err.attrs {
+			v.Attrs[attr.Key] = jsonValue(r.attr(attr))
+		}
+	}
+	for _, cause := range err.stack {
+		if oopsErr, ok := cause.(*Error); ok && depth < maxDepth {
+			v.Causes = append(v.Causes, r.toJSON(oopsErr, depth+1))
+			continue
+		}
+		v.Causes = append(v.Causes, &errorJSON{Message: r.text(cause.Error())})
+	}
+	return v
+}
+
+// jsonValue returns v, or its text if [encoding/json] cannot encode it, e.g. a NaN float,
+// so that a single value never fails the encoding of the whole error, as with [Error.MarshalBinary].
+func jsonValue(v any) any {
+	if _, err := json.Marshal(v); err != nil {
+		return fmt.Sprint(v)
+	}
+	return v
+}
+
+func (r renderer) logValue(err *Error) slog.Value {
+	attrs := make([]slog.Attr, 0, 14)
+	attrs = append(attrs, slog.String("message", r.text(err.Error())))
+	add := func(key, value string) {
+		if value != "" {
+			attrs = append(attrs, slog.String(key, value))
+		}
+	}
+	add("id", err.id)
+	add("label", labelText(err.label))
+	if len(err.params) > 0 {
+		add("template", r.text(err.msg))
+		group := make([]any, len(err.params))
+		for i, param := range err.params {
+			group[i] = slog.Any(param.Name, r.value(param.Value))
+		}
+		attrs = append(attrs, slog.Group("params", group...))
+	}
+	add("request_id", err.correlation.RequestID)
+	add("trace_id", err.correlation.TraceID)
+	add("span_id", err.correlation.SpanID)
+	if !err.occurrence.Time.IsZero() {
+		attrs = append(attrs, slog.Time("time", err.occurrence.Time))
+	}
+	add("host", err.occurrence.Host)
+	if err.occurrence.PID != 0 {
+		attrs = append(attrs, slog.Int("pid", err.occurrence.PID))
+	}
+	if len(err.hops) > 0 {
+		hops := make([]string, len(err.hops))
+		for i := range err.hops {
+			hops[i] = err.hops[i].String()
+		}
+		add("hops", strings.Join(hops, "; "))
+	}
+	if len(err.attrs) > 0 {
+		group := make([]any, len(err.attrs))
+		for i, attr := range err.attrs {
+			group[i] = slog.Any(attr.Key, r.attr(attr))
+		}
+		attrs = append(attrs, slog.Group("attrs", group...))
+	}
+	if causes := err.stack; len(causes) > 0 {
+		texts := make([]string, len(causes))
+		for i := range causes {
+			texts[i] = r.text(causes[i].Error())
+		}
+		add("causes", strings.Join(texts, "; "))
+	}
+	return slog.GroupValue(attrs...)
+}
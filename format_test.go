@@ -0,0 +1,115 @@
+package oops_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/piteego/oops"
+	"github.com/piteego/oops/example"
+)
+
+func TestError_Format(t *testing.T) {
+	inner := oops.New("entity not found", oops.Tag(example.NotFound.Error), oops.Because(errors.New("record not found")))
+	err := oops.New("failed to process", oops.Tag(example.Internal.Error), oops.Because(inner))
+	testCases := []struct {
+		format string
+		want   string
+	}{
+		{"%v", "failed to process"},
+		{"%s", "failed to process"},
+		{"%q", `"failed to process"`},
+		{"%d", "%!d(*oops.Error=failed to process)"},
+		{"%+v", strings.Join([]string{
+			"failed to process",
+			"    label: something went wrong",
+			"    causes:",
+			"        - entity not found",
+			"              label: resource not found",
+			"              causes:",
+			"                  - record not found",
+		}, "\n")},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.format, func(t *testing.T) {
+			if got := fmt.Sprintf(tc.format, err); got != tc.want {
+				t.Errorf("expected:\n%s\ngot:\n%s", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestError_MarshalJSON(t *testing.T) {
+	inner := oops.New("entity not found", oops.Tag(example.NotFound.Error), oops.Because(errors.New("record not found")))
+	err := oops.New("failed to process", oops.Tag(example.Internal.Error), oops.Because(inner))
+	got, jsonErr := json.Marshal(err)
+	if jsonErr != nil {
+		t.Fatalf("unexpected error: %v", jsonErr)
+	}
+	want := `{"message":"failed to process","label":"something went wrong","code":"1","causes":[{"message":"entity not found","label":"resource not found","code":"30","causes":[{"message":"record not found"}]}]}`
+	if string(got) != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+}
+
+func TestError_MarshalJSON_unsupported(t *testing.T) {
+	err := oops.New("failed", oops.With("f", func() {}), oops.With("c", make(chan int)), oops.With("nan", math.NaN()),
+		oops.With("inf", math.Inf(1)), oops.With("n", 1.5))
+	got, jsonErr := json.Marshal(err)
+	if jsonErr != nil {
+		t.Fatalf("unexpected error: %v", jsonErr)
+	}
+	var decoded struct {
+		Attrs map[string]any `json:"attrs"`
+	}
+	if jsonErr := json.Unmarshal(got, &decoded); jsonErr != nil {
+		t.Fatalf("unexpected error: %v", jsonErr)
+	}
+	if decoded.Attrs["nan"] != "NaN" || decoded.Attrs["inf"] != "+Inf" || decoded.Attrs["n"] != 1.5 {
+		t.Errorf("expected the values JSON cannot encode as texts, got %s", got)
+	}
+	if _, ok := decoded.Attrs["f"].(string); !ok {
+		t.Errorf("expected the func as a text, got %s", got)
+	}
+}
+
+func TestError_MarshalJSON_frames(t *testing.T) {
+	err := oops.Safe(func() error { panic("boom") })
+	got, jsonErr := json.Marshal(err)
+	if jsonErr != nil {
+		t.Fatalf("unexpected error: %v", jsonErr)
+	}
+	var decoded struct {
+		Frames []map[string]any `json:"frames"`
+	}
+	if jsonErr := json.Unmarshal(got, &decoded); jsonErr != nil || len(decoded.Frames) == 0 {
+		t.Fatalf("expected frames in %s", got)
+	}
+	for _, key := range []string{"function", "file", "line"} {
+		if _, ok := decoded.Frames[0][key]; !ok {
+			t.Errorf("expected the key %q in the frame %v", key, decoded.Frames[0])
+		}
+	}
+}
+
+func TestError_LogValue(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	logger.Error("request failed", "err", oops.New("failed to process", oops.Tag(example.Internal.Error), oops.Because(errors.New("cause"))))
+	want := `level=ERROR msg="request failed" err.message="failed to process" err.label="something went wrong" err.causes=cause` + "\n"
+	if buf.String() != want {
+		t.Errorf("expected %q, got %q", want, buf.String())
+	}
+}
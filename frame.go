@@ -0,0 +1,58 @@
+package oops
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Frame is a single function call in a captured goroutine stack.
+type Frame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+// String returns the frame as "function file:line".
+func (f Frame) String() string {
+	return f.Function + " " + f.File + ":" + strconv.Itoa(f.Line)
+}
+
+// maxFrames bounds the depth of captured stacks.
+const maxFrames = 64
+
+// callers captures the stack of the calling goroutine, skipping the given number of frames
+// above the caller of callers.
+func callers(skip int) []Frame {
+	pcs := make([]uintptr, maxFrames)
+	n := runtime.Callers(skip+2, pcs)
+	if n == 0 {
+		return nil
+	}
+	frames := runtime.CallersFrames(pcs[:n])
+	stack := make([]Frame, 0, n)
+	for {
+		frame, more := frames.Next()
+		stack = append(stack, Frame{Function: frame.Function, File: frame.File, Line: frame.Line})
+		if !more {
+			break
+		}
+	}
+	return stack
+}
+
+// panicking trims a stack captured inside a deferred function down to the frames
+// of the panicking code, dropping the deferred call and the runtime panic machinery.
+func panicking(stack []Frame) []Frame {
+	for i := range stack {
+		if stack[i].Function != "runtime.gopanic" {
+			continue
+		}
+		stack = stack[i+1:]
+		for len(stack) > 0 && strings.HasPrefix(stack[0].Function, "runtime.") {
+			stack = stack[1:]
+		}
+		return stack
+	}
+	return stack
+}
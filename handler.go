@@ -19,6 +19,8 @@ func Handle(err error, handlers ...Handler) error {
 			if handlers[i] != nil {
 				if oopsErr := handlers[i](err); oopsErr != nil {
 					Because(err)(oopsErr)
+					oopsErr.handledBy(err, handlers[i])
+					globalHooks.handled(err, oopsErr)
 					return oopsErr
 				}
 			}
@@ -26,3 +28,34 @@ func Handle(err error, handlers ...Handler) error {
 		return err
 	}
 }
+
+// HandleInto processes the error stored in errp using [Handle], and stores the result back.
+// It is meant to be deferred in functions with a named return error, so every return goes through the handlers:
+//
+//	func (r *Repo) FindUser(id int) (user *User, err error) {
+//		defer oops.HandleInto(&err, example.ErrMap.Handler(), example.HandleRepoErr("user"))
+//		...
+//	}
+//
+// It does nothing if errp is nil or holds a nil error.
+func HandleInto(errp *error, handlers ...Handler) {
+	if errp != nil && *errp != nil {
+		*errp = Handle(*errp, handlers...)
+	}
+}
+
+// Guard returns a function processing the error of a call returning a value and an error using [Handle],
+// and returning the value as is:
+//
+//	guard := oops.Guard[*User](example.HandleRepoErr("user"))
+//	return guard(r.db.FindUser(id))
+func Guard[T any](handlers ...Handler) func(T, error) (T, error) {
+	return func(value T, err error) (T, error) {
+		return value, Handle(err, handlers...)
+	}
+}
+
+// Call calls fn and processes the returned error using [Handle].
+func Call(fn func() error, handlers ...Handler) error {
+	return Handle(fn(), handlers...)
+}
@@ -1,6 +1,7 @@
 package oops_test
 
 import (
+	"errors"
 	"github.com/piteego/oops"
 	"github.com/piteego/oops/example"
 	"testing"
@@ -38,3 +39,41 @@ func TestHandle(t *testing.T) {
 		})
 	}
 }
+
+func TestHandleInto(t *testing.T) {
+	find := func(cause error) (err error) {
+		defer oops.HandleInto(&err, example.ErrMap.Handler(), example.HandleRepoErr("user"))
+		return cause
+	}
+	if err := find(nil); err != nil {
+		t.Errorf("expected nil, got %v", err)
+	}
+	if err := find(example.RedisCacheMissed); !errors.Is(err, example.NotFound.Error) || err.Error() != "cache key not found" {
+		t.Errorf("expected the map to handle the error, got %v", err)
+	}
+	if err := find(example.OsErrNotExist); !errors.Is(err, example.Internal.Error) || !errors.Is(err, example.OsErrNotExist) {
+		t.Errorf("expected the handler to handle the error, got %v", err)
+	}
+	oops.HandleInto(nil, example.HandleRepoErr("user")) // must not panic
+}
+
+func TestGuard(t *testing.T) {
+	guard := oops.Guard[int](example.HandleRepoErr("user"))
+	if value, err := guard(42, nil); value != 42 || err != nil {
+		t.Errorf("expected 42 and nil, got %v and %v", value, err)
+	}
+	value, err := guard(0, example.GormErrDuplicatedKey)
+	if value != 0 || !errors.Is(err, example.Duplication.Error) {
+		t.Errorf("expected a duplication error, got %v and %v", value, err)
+	}
+}
+
+func TestCall(t *testing.T) {
+	if err := oops.Call(func() error { return nil }, example.HandleRepoErr("user")); err != nil {
+		t.Errorf("expected nil, got %v", err)
+	}
+	err := oops.Call(func() error { return example.GormErrRecordNotFound }, example.HandleRepoErr("user"))
+	if !errors.Is(err, example.NotFound.Error) || err.Error() != "user not found" {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+}
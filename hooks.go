@@ -0,0 +1,102 @@
+package oops
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// Hooks is a registry of observers invoked when errors are created or handled, e.g. to count errors per [Label].
+// The zero value is ready to use and all methods are safe for concurrent use.
+// Package level [OnNew] and [OnHandle] register global observers; a scoped *Hooks is attached
+// to a context using [ContextWithHooks].
+type Hooks struct {
+	onNew    hookList[func(*Error)]
+	onHandle hookList[func(error, *Error)]
+}
+
+// OnNew registers fn to be called with every *[Error] created. The returned function unregisters fn,
+// which is handy in tests:
+//
+//	t.Cleanup(hooks.OnNew(func(err *oops.Error) { ... }))
+func (h *Hooks) OnNew(fn func(*Error)) (unregister func()) { return h.onNew.add(fn) }
+
+// OnHandle registers fn to be called with the original error and the resulting *[Error]
+// whenever an error is converted by a handler or a [Map]. The returned function unregisters fn.
+func (h *Hooks) OnHandle(fn func(original error, result *Error)) (unregister func()) {
+	return h.onHandle.add(fn)
+}
+
+func (h *Hooks) created(err *Error) {
+	for _, fn := range h.onNew.load() {
+		(*fn)(err)
+	}
+}
+
+func (h *Hooks) handled(original error, result *Error) {
+	for _, fn := range h.onHandle.load() {
+		(*fn)(original, result)
+	}
+}
+
+var globalHooks Hooks
+
+// OnNew registers fn to be called with every *[Error] created by [New] and its variants.
+// The returned function unregisters fn.
+func OnNew(fn func(*Error)) (unregister func()) { return globalHooks.OnNew(fn) }
+
+// OnHandle registers fn to be called with the original error and the resulting *[Error]
+// whenever an error is converted by [Handle], [HandleContext] or [Map.Handle]. The returned function unregisters fn.
+func OnHandle(fn func(original error, result *Error)) (unregister func()) {
+	return globalHooks.OnHandle(fn)
+}
+
+type hooksKey struct{}
+
+// ContextWithHooks returns a copy of ctx carrying scoped hooks, invoked by [NewCtx] and [HandleContext]
+// in addition to the global ones.
+func ContextWithHooks(ctx context.Context, hooks *Hooks) context.Context {
+	return context.WithValue(ctx, hooksKey{}, hooks)
+}
+
+func hooksFrom(ctx context.Context) *Hooks {
+	if ctx == nil {
+		return nil
+	}
+	hooks, _ := ctx.Value(hooksKey{}).(*Hooks)
+	return hooks
+}
+
+// hookList is a copy-on-write list of functions, so invoking an empty list costs a single atomic load.
+type hookList[F any] struct {
+	mu   sync.Mutex
+	list atomic.Pointer[[]*F]
+}
+
+func (l *hookList[F]) load() []*F {
+	if list := l.list.Load(); list != nil {
+		return *list
+	}
+	return nil
+}
+
+func (l *hookList[F]) add(fn F) func() {
+	entry := &fn
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	current := l.load()
+	list := append(current[:len(current):len(current)], entry)
+	l.list.Store(&list)
+	return func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		current := l.load()
+		for i := range current {
+			if current[i] == entry {
+				list := append(current[:i:i], current[i+1:]...)
+				l.list.Store(&list)
+				return
+			}
+		}
+	}
+}
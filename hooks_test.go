@@ -0,0 +1,90 @@
+package oops_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/piteego/oops"
+	"github.com/piteego/oops/example"
+)
+
+func TestOnNew(t *testing.T) {
+	var created []*oops.Error
+	unregister := oops.OnNew(func(err *oops.Error) { created = append(created, err) })
+	err := oops.New("failed to process", oops.Tag(example.Internal.Error))
+	unregister()
+	_ = oops.New("after unregister")
+	if len(created) != 1 || created[0] != err {
+		t.Fatalf("expected hook to observe %v, got %v", err, created)
+	}
+	if !errors.Is(created[0], example.Internal.Error) {
+		t.Errorf("expected hook to observe a fully built error")
+	}
+}
+
+func TestOnHandle(t *testing.T) {
+	type call struct {
+		original error
+		result   *oops.Error
+	}
+	var calls []call
+	t.Cleanup(oops.OnHandle(func(original error, result *oops.Error) {
+		calls = append(calls, call{original, result})
+	}))
+	_ = oops.Handle(example.GormErrRecordNotFound, example.HandleRepoErr("user"))
+	_ = example.ErrMap.Handle(example.RedisCacheMissed)
+	_ = oops.HandleContext(context.Background(), context.Canceled)
+	_ = oops.Handle(errors.New("unhandled error"), nil)
+	want := []error{example.GormErrRecordNotFound, example.RedisCacheMissed, context.Canceled}
+	if len(calls) != len(want) {
+		t.Fatalf("expected %d calls, got %d", len(want), len(calls))
+	}
+	for i := range want {
+		if calls[i].original != want[i] || !errors.Is(calls[i].result, want[i]) {
+			t.Errorf("expected call with %v, got %v -> %v", want[i], calls[i].original, calls[i].result)
+		}
+	}
+}
+
+func TestContextWithHooks(t *testing.T) {
+	var hooks oops.Hooks
+	var created, handled int
+	hooks.OnNew(func(*oops.Error) { created++ })
+	unregister := hooks.OnHandle(func(error, *oops.Error) { handled++ })
+	ctx := oops.ContextWithHooks(context.Background(), &hooks)
+	_ = oops.NewCtx(ctx, "failed to process")
+	_ = oops.New("not scoped")
+	_ = oops.HandleContext(ctx, example.GormErrRecordNotFound, example.HandleRepoErr("user").Ctx())
+	_ = oops.Handle(example.GormErrRecordNotFound, example.HandleRepoErr("user"))
+	unregister()
+	_ = oops.HandleContext(ctx, example.GormErrRecordNotFound, example.HandleRepoErr("user").Ctx())
+	if created != 1 || handled != 1 {
+		t.Errorf("expected scoped hooks to be invoked once each, got %d created and %d handled", created, handled)
+	}
+}
+
+func TestHooks_Concurrent(t *testing.T) {
+	var hooks oops.Hooks
+	var mu sync.Mutex
+	count := 0
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			unregister := hooks.OnNew(func(*oops.Error) {
+				mu.Lock()
+				count++
+				mu.Unlock()
+			})
+			_ = oops.NewCtx(oops.ContextWithHooks(context.Background(), &hooks), "concurrent")
+			unregister()
+		}()
+	}
+	wg.Wait()
+	if count < 8 {
+		t.Errorf("expected each goroutine to observe at least its own error, got %d calls", count)
+	}
+}
@@ -0,0 +1,205 @@
+// Package httperr converts the responses of downstream HTTP services into *[oops.Error] values.
+//
+// A non-2xx response becomes a [StatusError] cause, classified by [Handler]:
+//
+//   - an application/problem+json body whose code is registered (see [oops.LookupCode]) is tagged with the
+//     [oops.Label] of the code, and keeps its detail as message;
+//   - otherwise the label is derived from the status code, e.g. 404 as [oops.NotFound], 409 as [oops.AlreadyExists],
+//     and 429, 502 and 503 as [oops.Unavailable], so that [oops.Retryable] reports them as retryable.
+//
+// The *[oops.Error] carries the method, the URL with its credentials and query values redacted,
+// the status code and the beginning of the body as attributes. Transport errors are classified by [oops.Stdlib].
+// Custom handlers given to [Wrap] or [Check] take precedence, including over context errors such as timeouts:
+//
+//	client := httperr.Wrap(http.DefaultClient)
+//	resp, err := client.Do(req)
+//	if errors.Is(err, oops.NotFound) { ... }
+package httperr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/piteego/oops"
+)
+
+// maxBody bounds the number of bytes of a response body kept by a [StatusError].
+const maxBody = 1 << 10
+
+// Problem is an RFC 9457 Problem Details body, with the code extension written by the catalog package.
+type Problem struct {
+	Type     string `json:"type,omitempty"`
+	Title    string `json:"title,omitempty"`
+	Status   int    `json:"status,omitempty"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	Code     string `json:"code,omitempty"`
+}
+
+// StatusError is the cause of the *[oops.Error] returned for a non-2xx response.
+type StatusError struct {
+	Method     string
+	URL        string // redacted, see [Check]
+	StatusCode int
+	Body       string   // the first 1 KiB of the body, followed by "..." if it is longer
+	Problem    *Problem // decoded from an application/problem+json body, if any
+}
+
+// Error implements golang's builtin error interface, e.g. "GET https://api.example.com/users/42: 404 Not Found".
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s %s: %d %s", e.Method, e.URL, e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// statusLabels derives the label of a response without a registered problem code from its status code.
+var statusLabels = map[int]oops.Label{
+	http.StatusBadRequest:          oops.InvalidInput,
+	http.StatusUnauthorized:        oops.PermissionDenied,
+	http.StatusForbidden:           oops.PermissionDenied,
+	http.StatusNotFound:            oops.NotFound,
+	http.StatusRequestTimeout:      oops.Timeout,
+	http.StatusConflict:            oops.AlreadyExists,
+	http.StatusPreconditionFailed:  oops.FailedPrecondition,
+	http.StatusUnprocessableEntity: oops.InvalidInput,
+	http.StatusTooManyRequests:     oops.Unavailable,
+	499:                            oops.Canceled,
+	http.StatusBadGateway:          oops.Unavailable,
+	http.StatusServiceUnavailable:  oops.Unavailable,
+	http.StatusGatewayTimeout:      oops.Timeout,
+}
+
+// Handler classifies a [StatusError], by the code of its [Problem] if it is registered, or by its status code.
+// Statuses without a predefined label are left [oops.Untagged].
+func Handler(err error) *oops.Error {
+	statusErr, ok := err.(*StatusError)
+	if !ok {
+		return nil
+	}
+	options := []oops.ErrorOption{
+		oops.With("method", statusErr.Method),
+		oops.With("url", statusErr.URL),
+		oops.With("status", statusErr.StatusCode),
+	}
+	if statusErr.Body != "" {
+		options = append(options, oops.With("body", statusErr.Body))
+	}
+	msg := fmt.Sprintf("%s %s responded %d %s",
+		statusErr.Method, statusErr.URL, statusErr.StatusCode, http.StatusText(statusErr.StatusCode))
+	if p := statusErr.Problem; p != nil {
+		if def, ok := oops.LookupCode(p.Code); ok {
+			options = append(options, oops.Tag(def.Label))
+		}
+		if p.Code != "" {
+			options = append(options, oops.With("code", p.Code))
+		}
+		switch {
+		case p.Detail != "":
+			msg = p.Detail
+		case p.Title != "":
+			msg = p.Title
+		}
+	}
+	if label, ok := statusLabels[statusErr.StatusCode]; ok {
+		options = append(options, oops.Tag(label))
+	}
+	return oops.New(msg, options...).(*oops.Error)
+}
+
+// Check returns nil if resp has a 2xx status code. Otherwise, it reads the beginning of the body, closes it,
+// and returns the [StatusError] classified by the given handlers, then [Handler].
+func Check(resp *http.Response, handlers ...oops.Handler) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	return handle(resp.Request, newStatusError(resp), handlers)
+}
+
+func newStatusError(resp *http.Response) *StatusError {
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxBody+1))
+	statusErr := &StatusError{StatusCode: resp.StatusCode, Body: string(body)}
+	if len(body) > maxBody {
+		statusErr.Body = string(body[:maxBody]) + "..."
+	}
+	if req := resp.Request; req != nil {
+		statusErr.Method = req.Method
+		statusErr.URL = redactURL(req.URL)
+	}
+	if mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mediaType == "application/problem+json" {
+		var p Problem
+		if json.Unmarshal(body, &p) == nil {
+			statusErr.Problem = &p
+		}
+	}
+	return statusErr
+}
+
+// handle classifies err with the custom handlers, [Handler] and [oops.Stdlib],
+// stamping the result with the correlation identifiers of the context of req.
+func handle(req *http.Request, err error, custom []oops.Handler) error {
+	handlers := append(append(custom[:len(custom):len(custom)], Handler), oops.Stdlib()...)
+	if req == nil {
+		return oops.Handle(err, handlers...)
+	}
+	handlersCtx := make([]oops.HandlerCtx, len(handlers))
+	for i := range handlers {
+		handlersCtx[i] = handlers[i].Ctx()
+	}
+	return oops.HandleContext(req.Context(), err, handlersCtx...)
+}
+
+// redactURL returns u without its password, query values and fragment, which may hold credentials.
+func redactURL(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+	redacted := *u
+	redacted.Fragment, redacted.RawFragment = "", ""
+	if query := u.Query(); len(query) > 0 {
+		keys := make([]string, 0, len(query))
+		for key := range query {
+			keys = append(keys, url.QueryEscape(key)+"="+oops.Redacted)
+		}
+		sort.Strings(keys)
+		redacted.RawQuery = strings.Join(keys, "&")
+	}
+	return redacted.Redacted()
+}
+
+// Client wraps an [http.Client], converting non-2xx responses and transport errors into *[oops.Error] values.
+type Client struct {
+	client   *http.Client
+	handlers []oops.Handler
+}
+
+// Wrap returns a [Client] sending requests with client, or [http.DefaultClient] if it is nil,
+// and using the given handlers before the built-in classification.
+func Wrap(client *http.Client, handlers ...oops.Handler) *Client {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Client{client: client, handlers: handlers}
+}
+
+// Do sends the request as [http.Client.Do] does. A non-2xx response is closed and returned as an error,
+// see [Check]; the caller must close the body of a 2xx response. The URL of a transport error is redacted.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	resp, err := c.client.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			urlErr.URL = redactURL(req.URL)
+		}
+		return nil, handle(req, err, c.handlers)
+	}
+	if err := Check(resp, c.handlers...); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
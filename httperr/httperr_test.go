@@ -0,0 +1,223 @@
+package httperr_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/piteego/oops"
+	"github.com/piteego/oops/example"
+	"github.com/piteego/oops/httperr"
+	"github.com/piteego/oops/oopstest"
+)
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ok", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "hello")
+	})
+	mux.HandleFunc("/status/{code}", func(w http.ResponseWriter, r *http.Request) {
+		var code int
+		fmt.Sscan(r.PathValue("code"), &code)
+		w.WriteHeader(code)
+		io.WriteString(w, "status body")
+	})
+	mux.HandleFunc("/problem/{code}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json; charset=utf-8")
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprintf(w, `{"title":"resource not found","status":404,"detail":"user 42 not found","code":%q}`, r.PathValue("code"))
+	})
+	mux.HandleFunc("/large", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		io.WriteString(w, strings.Repeat("x", 4096))
+	})
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	})
+	mux.HandleFunc("/drop", func(w http.ResponseWriter, r *http.Request) {
+		conn, _, _ := http.NewResponseController(w).Hijack()
+		conn.Close()
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func do(t *testing.T, client *httperr.Client, ctx context.Context, url string) error {
+	t.Helper()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := client.Do(req)
+	if err == nil {
+		resp.Body.Close()
+	}
+	return err
+}
+
+func TestClient_Do_ok(t *testing.T) {
+	srv := newServer(t)
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/ok", nil)
+	resp, err := httperr.Wrap(srv.Client()).Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if body, _ := io.ReadAll(resp.Body); string(body) != "hello" {
+		t.Errorf("expected the body to be readable, got %q", body)
+	}
+}
+
+func TestClient_Do_status(t *testing.T) {
+	srv := newServer(t)
+	client := httperr.Wrap(srv.Client())
+	testCases := []struct {
+		status    int
+		label     oops.Label
+		retryable bool
+	}{
+		{status: http.StatusBadRequest, label: oops.InvalidInput},
+		{status: http.StatusForbidden, label: oops.PermissionDenied},
+		{status: http.StatusNotFound, label: oops.NotFound},
+		{status: http.StatusConflict, label: oops.AlreadyExists},
+		{status: http.StatusTooManyRequests, label: oops.Unavailable, retryable: true},
+		{status: http.StatusServiceUnavailable, label: oops.Unavailable, retryable: true},
+		{status: http.StatusGatewayTimeout, label: oops.Timeout, retryable: true},
+		{status: http.StatusInternalServerError, label: oops.Untagged},
+	}
+	for _, tc := range testCases {
+		t.Run(http.StatusText(tc.status), func(t *testing.T) {
+			err := do(t, client, context.Background(), fmt.Sprintf("%s/status/%d?token=secret", srv.URL, tc.status))
+			oopstest.AssertLabel(t, err, tc.label)
+			if got := oops.Retryable(err); got != tc.retryable {
+				t.Errorf("expected retryable %v, got %v", tc.retryable, got)
+			}
+			var statusErr *httperr.StatusError
+			if !errors.As(err, &statusErr) || statusErr.StatusCode != tc.status || statusErr.Body != "status body" {
+				t.Fatalf("expected a StatusError cause, got %#v", statusErr)
+			}
+			wantURL := fmt.Sprintf("%s/status/%d?token=%s", srv.URL, tc.status, oops.Redacted)
+			for key, want := range map[string]any{"method": "GET", "url": wantURL, "status": tc.status, "body": "status body"} {
+				oopstest.AssertAttr(t, err, key, want)
+			}
+			if strings.Contains(err.Error(), "secret") {
+				t.Errorf("expected the query values to be redacted, got %q", err)
+			}
+		})
+	}
+}
+
+func TestClient_Do_problem(t *testing.T) {
+	srv := newServer(t)
+	client := httperr.Wrap(srv.Client())
+	t.Run("registered code", func(t *testing.T) {
+		err := do(t, client, context.Background(), srv.URL+"/problem/30")
+		oopstest.AssertLabel(t, err, example.NotFound.Error)
+		oopstest.AssertMessage(t, err, "user 42 not found")
+		var statusErr *httperr.StatusError
+		if errors.As(err, &statusErr); statusErr.Problem == nil || statusErr.Problem.Code != "30" {
+			t.Errorf("expected the decoded problem, got %+v", statusErr.Problem)
+		}
+	})
+	t.Run("unknown code", func(t *testing.T) {
+		err := do(t, client, context.Background(), srv.URL+"/problem/UNKNOWN")
+		oopstest.AssertLabel(t, err, oops.NotFound)
+		oopstest.AssertAttr(t, err, "code", "UNKNOWN")
+	})
+}
+
+func TestClient_Do_truncatesBody(t *testing.T) {
+	srv := newServer(t)
+	var statusErr *httperr.StatusError
+	if err := do(t, httperr.Wrap(srv.Client()), context.Background(), srv.URL+"/large"); !errors.As(err, &statusErr) {
+		t.Fatalf("expected a StatusError, got %v", err)
+	}
+	if len(statusErr.Body) != 1<<10+len("...") || !strings.HasSuffix(statusErr.Body, "...") {
+		t.Errorf("expected a truncated body, got %d bytes", len(statusErr.Body))
+	}
+}
+
+func TestClient_Do_transportErrors(t *testing.T) {
+	srv := newServer(t)
+	client := httperr.Wrap(srv.Client())
+	t.Run("deadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		oopstest.AssertLabel(t, do(t, client, ctx, srv.URL+"/slow"), oops.DeadlineExceeded)
+	})
+	t.Run("dropped connection", func(t *testing.T) {
+		err := do(t, client, context.Background(), srv.URL+"/drop")
+		oopstest.AssertLabel(t, err, oops.Unavailable)
+		if !oops.Retryable(err) {
+			t.Errorf("expected a retryable error, got %+v", err)
+		}
+	})
+	t.Run("unavailable", func(t *testing.T) {
+		closed := httptest.NewServer(http.NotFoundHandler())
+		closed.Close()
+		err := do(t, client, context.Background(), closed.URL+"/?token=secret")
+		oopstest.AssertLabel(t, err, oops.Unavailable)
+		if strings.Contains(fmt.Sprintf("%+v", err), "secret") {
+			t.Errorf("expected the URL to be redacted, got %+v", err)
+		}
+	})
+}
+
+func TestClient_Do_customHandlers(t *testing.T) {
+	srv := newServer(t)
+	conflict := func(err error) *oops.Error {
+		var statusErr *httperr.StatusError
+		if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusConflict {
+			return oops.New("duplicated entity", oops.Tag(example.Duplication.Error)).(*oops.Error)
+		}
+		return nil
+	}
+	err := do(t, httperr.Wrap(srv.Client(), conflict), context.Background(), srv.URL+"/status/409")
+	if !errors.Is(err, example.Duplication.Error) || err.Error() != "duplicated entity" {
+		t.Errorf("expected the custom handler to take precedence, got %v", err)
+	}
+}
+
+func TestClient_Do_customHandlers_timeout(t *testing.T) {
+	srv := newServer(t)
+	timeout := func(err error) *oops.Error {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return oops.New("downstream timed out", oops.Tag(example.Unprocessable.Error)).(*oops.Error)
+		}
+		return nil
+	}
+	client := srv.Client()
+	client.Timeout = 10 * time.Millisecond
+	err := do(t, httperr.Wrap(client), context.Background(), srv.URL+"/slow")
+	oopstest.AssertLabel(t, err, oops.DeadlineExceeded)
+	err = do(t, httperr.Wrap(client, timeout), context.Background(), srv.URL+"/slow")
+	oopstest.AssertLabel(t, err, example.Unprocessable.Error)
+	oopstest.AssertMessage(t, err, "downstream timed out")
+}
+
+func TestCheck(t *testing.T) {
+	srv := newServer(t)
+	resp, err := srv.Client().Get(srv.URL + "/status/404")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := httperr.Check(resp); !errors.Is(err, oops.NotFound) {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+	resp, err = srv.Client().Get(srv.URL + "/ok")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if err := httperr.Check(resp); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
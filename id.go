@@ -0,0 +1,108 @@
+package oops
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"sync"
+)
+
+// crockford is the Crockford's base32 alphabet used by instance IDs, without I, L, O and U.
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ids holds the state of [NewID], to keep the IDs generated within the same millisecond sorted.
+var ids struct {
+	sync.Mutex
+	ms      uint64
+	entropy [10]byte
+}
+
+// NewID returns a new unique instance ID, in the ULID format: 26 URL-safe characters encoding
+// the milliseconds of the clock (see [SetClock]) in the first 10 characters, followed by 80 random bits.
+// IDs sort in the order they were generated, including within the same millisecond, as their random part
+// is then incremented, unless the clock is replaced in between.
+func NewID() string {
+	ms := uint64(now().UnixMilli())
+	ids.Lock()
+	if ms > ids.ms {
+		ids.ms = ms
+		rand.Read(ids.entropy[:])
+	} else {
+		// Same millisecond, or the clock went backwards: keep the last time and increment the random part.
+		carry := true
+		for i := len(ids.entropy) - 1; i >= 0 && carry; i-- {
+			ids.entropy[i]++
+			carry = ids.entropy[i] == 0
+		}
+		if carry {
+			ids.ms++
+		}
+	}
+	var id [16]byte
+	binary.BigEndian.PutUint16(id[0:], uint16(ids.ms>>32))
+	binary.BigEndian.PutUint32(id[2:], uint32(ids.ms))
+	copy(id[6:], ids.entropy[:])
+	ids.Unlock()
+	return encodeID(id)
+}
+
+// resetIDs forgets the time of the last ID generated, once the clock is replaced, see [SetClock].
+func resetIDs() {
+	ids.Lock()
+	ids.ms = 0
+	ids.Unlock()
+}
+
+// encodeID encodes the 128 bits of id, preceded by 2 zero bits, as 26 characters of 5 bits.
+func encodeID(id [16]byte) string {
+	var out [26]byte
+	for i := range out {
+		var v byte
+		for bit := i*5 - 2; bit < i*5+3; bit++ {
+			v <<= 1
+			if bit >= 0 && id[bit/8]&(0x80>>(bit%8)) != 0 {
+				v |= 1
+			}
+		}
+		out[i] = crockford[v]
+	}
+	return string(out[:])
+}
+
+// Identify assigns a new instance ID to the *[Error] being created, see [NewID], unless it already has one.
+// Like other options, it is meant for [New] and its variants: use [EnsureID] to identify an existing error,
+// which may be shared.
+func Identify() ErrorOption {
+	return func(err *Error) {
+		if err.id == "" {
+			err.id = NewID()
+		}
+	}
+}
+
+// EnsureID returns err along with the instance ID of its outermost *[Error], giving it one if it has none.
+// It is meant to be called at the edge of the application, e.g. by the HTTP handler writing the response,
+// so that every *[Error] reaching it gets an ID users can quote:
+//
+//	err, id := oops.EnsureID(err)
+//
+// err is never modified, so EnsureID is safe on errors shared across goroutines, such as package-level errors:
+// if the outermost *[Error] has no ID, the returned error is a copy of it with a new ID, without the errors
+// wrapping it in err, if any. It returns err and an empty string if err has no *[Error].
+func EnsureID(err error) (error, string) {
+	var oopsErr *Error
+	if !errors.As(err, &oopsErr) {
+		return err, ""
+	}
+	if oopsErr.id != "" {
+		return err, oopsErr.id
+	}
+	identified := oopsErr.clone()
+	identified.id = NewID()
+	return identified, identified.id
+}
+
+// ID returns the instance ID of the error, or an empty string if it has none, see [Identify] and [EnsureID].
+// The result of [Handle] and its variants keeps the ID of the *[Error] it was converted from, if any,
+// so the ID stays the same across layers.
+func (err *Error) ID() string { return err.id }
@@ -0,0 +1,216 @@
+package oops_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/piteego/oops"
+	"github.com/piteego/oops/example"
+)
+
+// idTime decodes the milliseconds encoded in the first 10 characters of an instance ID.
+func idTime(id string) time.Time {
+	var ms int64
+	for _, c := range id[:10] {
+		ms = ms<<5 | int64(strings.IndexRune("0123456789ABCDEFGHJKMNPQRSTVWXYZ", c))
+	}
+	return time.UnixMilli(ms).UTC()
+}
+
+func TestNewID(t *testing.T) {
+	fixed := time.Date(2026, 1, 2, 3, 4, 5, 678_000_000, time.UTC)
+	t.Cleanup(oops.SetClock(func() time.Time { return fixed }))
+	generated := make([]string, 1000)
+	for i := range generated {
+		generated[i] = oops.NewID()
+	}
+	for _, id := range generated {
+		if len(id) != 26 || strings.Trim(id, "0123456789ABCDEFGHJKMNPQRSTVWXYZ") != "" {
+			t.Fatalf("expected 26 Crockford base32 characters, got %q", id)
+		}
+		if got := idTime(id); !got.Equal(fixed) {
+			t.Fatalf("expected the time %v in %q, got %v", fixed, id, got)
+		}
+	}
+	if !sort.StringsAreSorted(generated) {
+		t.Errorf("expected the IDs generated within the same millisecond to be sorted")
+	}
+	for i := 1; i < len(generated); i++ {
+		if generated[i] == generated[i-1] {
+			t.Fatalf("expected unique IDs, got %q twice", generated[i])
+		}
+	}
+
+	fixed = fixed.Add(-time.Hour)
+	if id := oops.NewID(); id <= generated[len(generated)-1] {
+		t.Errorf("expected IDs to stay sorted when the clock goes backwards, got %q after %q", id, generated[len(generated)-1])
+	}
+}
+
+func TestNewID_clock(t *testing.T) {
+	future := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Millisecond)
+	restore := oops.SetClock(func() time.Time { return future })
+	if got := idTime(oops.NewID()); !got.Equal(future) {
+		t.Errorf("expected the time of the clock %v, got %v", future, got)
+	}
+	restore()
+	if got := idTime(oops.NewID()); !got.Before(future) {
+		t.Errorf("expected the restored clock not to be stuck at %v", future)
+	}
+	past := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	t.Cleanup(oops.SetClock(func() time.Time { return past }))
+	if got := idTime(oops.NewID()); !got.Equal(past) {
+		t.Errorf("expected the time of a clock in the past %v, got %v", past, got)
+	}
+}
+
+func TestNewID_concurrent(t *testing.T) {
+	var mu sync.Mutex
+	seen := map[string]bool{}
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 500; j++ {
+				id := oops.NewID()
+				mu.Lock()
+				if seen[id] {
+					t.Errorf("duplicated ID %q", id)
+				}
+				seen[id] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestIdentify(t *testing.T) {
+	err := oops.New("failed", oops.Identify()).(*oops.Error)
+	id := err.ID()
+	if id == "" {
+		t.Fatalf("expected an ID")
+	}
+	oops.Identify()(err)
+	if err.ID() != id {
+		t.Errorf("expected Identify to keep the existing ID %q, got %q", id, err.ID())
+	}
+	if oops.New("failed").(*oops.Error).ID() != "" {
+		t.Errorf("expected no ID by default")
+	}
+}
+
+func TestEnsureID(t *testing.T) {
+	plain := errors.New("plain")
+	if got, id := oops.EnsureID(plain); got != plain || id != "" {
+		t.Errorf("expected no ID without *oops.Error, got %v and %q", got, id)
+	}
+	if got, id := oops.EnsureID(nil); got != nil || id != "" {
+		t.Errorf("expected no ID for nil, got %v and %q", got, id)
+	}
+	err := oops.New("failed", oops.Tag(example.Internal.Error))
+	identified, id := oops.EnsureID(err)
+	if id == "" || identified.(*oops.Error).ID() != id || !errors.Is(identified, example.Internal.Error) {
+		t.Errorf("expected an identified copy, got %+v and %q", identified, id)
+	}
+	if err.(*oops.Error).ID() != "" {
+		t.Errorf("expected the input not to be modified, got %q", err.(*oops.Error).ID())
+	}
+	if again, againID := oops.EnsureID(identified); again != identified || againID != id {
+		t.Errorf("expected an identified error to be returned as is, got %v and %q", again, againID)
+	}
+	wrapped := fmt.Errorf("edge: %w", identified)
+	if got, gotID := oops.EnsureID(wrapped); got != wrapped || gotID != id {
+		t.Errorf("expected the ID of the wrapped error, got %v and %q", got, gotID)
+	}
+}
+
+func TestEnsureID_shared(t *testing.T) {
+	errGone := oops.New("gone", oops.Tag(example.NotFound.Error))
+	ids := make([]string, 8)
+	var wg sync.WaitGroup
+	for i := range ids {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, ids[i] = oops.EnsureID(errGone)
+		}()
+	}
+	wg.Wait()
+	seen := map[string]bool{}
+	for _, id := range ids {
+		if id == "" || seen[id] {
+			t.Errorf("expected a unique ID for every request, got %q", ids)
+			break
+		}
+		seen[id] = true
+	}
+	if errGone.(*oops.Error).ID() != "" {
+		t.Errorf("expected the shared error to stay unidentified")
+	}
+}
+
+func TestError_ID_propagation(t *testing.T) {
+	repoErr := oops.New("user not found", oops.Tag(example.NotFound.Error), oops.Identify())
+	id := repoErr.(*oops.Error).ID()
+	wrapped := fmt.Errorf("get profile: %w", repoErr)
+
+	handled := oops.Handle(wrapped, oops.Fallback(example.Internal.Error, "profile unavailable"))
+	if got := handled.(*oops.Error).ID(); got != id {
+		t.Errorf("expected Handle to keep the ID %q, got %q", id, got)
+	}
+	identified := func(error) *oops.Error { return oops.New("other", oops.Identify()).(*oops.Error) }
+	if got := oops.Handle(wrapped, identified).(*oops.Error).ID(); got != id {
+		t.Errorf("expected Handle to replace the ID of the result with %q, got %q", id, got)
+	}
+	fallback := oops.Fallback(example.Internal.Error, "failed").Ctx()
+	if got := oops.HandleContext(context.Background(), wrapped, fallback).(*oops.Error).ID(); got != id {
+		t.Errorf("expected HandleContext to keep the ID %q, got %q", id, got)
+	}
+	if got := oops.Handle(errors.New("plain"), oops.Fallback(example.Internal.Error, "failed")).(*oops.Error).ID(); got != "" {
+		t.Errorf("expected no ID without an identified cause, got %q", got)
+	}
+
+	t.Run("map", func(t *testing.T) {
+		sentinel := errors.New("sentinel")
+		template := oops.New("mapped", oops.Identify()).(*oops.Error)
+		m := oops.Map{sentinel: template}
+		first, second := m.Handle(sentinel).(*oops.Error), m.Handle(sentinel).(*oops.Error)
+		if first.ID() == "" || first.ID() == template.ID() || first.ID() == second.ID() {
+			t.Errorf("expected every copy of an identified template to get its own ID, got %q, %q and %q",
+				template.ID(), first.ID(), second.ID())
+		}
+	})
+}
+
+func TestError_ID_serialization(t *testing.T) {
+	err := oops.New("failed", oops.Because(oops.New("cause", oops.Identify())), oops.Identify()).(*oops.Error)
+	if verbose := fmt.Sprintf("%+v", err); !strings.Contains(verbose, "failed\n    id: "+err.ID()+"\n") {
+		t.Errorf("expected the ID in:\n%s", verbose)
+	}
+	if got := err.LogValue().Group()[1]; got.Key != "id" || got.Value.String() != err.ID() {
+		t.Errorf("expected the ID to be logged, got %v", got)
+	}
+	decoded := roundTrip(t, err)
+	if decoded.ID() != err.ID() || decoded.Causes()[0].(*oops.Error).ID() != err.Causes()[0].(*oops.Error).ID() {
+		t.Errorf("expected JSON to keep the IDs, got %+v", decoded)
+	}
+	data, binErr := err.MarshalBinary()
+	if binErr != nil {
+		t.Fatalf("unexpected error: %v", binErr)
+	}
+	var binDecoded oops.Error
+	if binErr := binDecoded.UnmarshalBinary(data); binErr != nil {
+		t.Fatalf("unexpected error: %v", binErr)
+	}
+	if got, want := fmt.Sprintf("%+v", &binDecoded), fmt.Sprintf("%+v", err); got != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, got)
+	}
+}
@@ -6,10 +6,27 @@ type Map map[error]*Error
 // Handle processes an error using the Map, returning the corresponding *[Error] if it exists.
 // If the error is not found in the Map, it returns the original error.
 // It also appends the original error to the stack of the returned *[Error] using [Because].
+// The returned *[Error] is a copy, so the *[Error] values of the Map are never modified.
 func (m Map) Handle(err error) error {
-	if oopsErr, exists := m[err]; exists {
+	if template, exists := m[err]; exists {
+		oopsErr := template.clone()
+		oopsErr.stamp()
 		Because(err)(oopsErr)
+		oopsErr.handledBy(err, "oops.Map.Handle")
+		globalHooks.handled(err, oopsErr)
 		return oopsErr
 	}
 	return err
 }
+
+// Handler adapts the Map to a [Handler] that returns a copy of the corresponding *[Error] if it exists, or nil otherwise.
+func (m Map) Handler() Handler {
+	return func(err error) *Error {
+		if template, exists := m[err]; exists {
+			oopsErr := template.clone()
+			oopsErr.stamp()
+			return oopsErr
+		}
+		return nil
+	}
+}
@@ -43,3 +43,35 @@ func TestMap_Handle(t *testing.T) {
 	})
 
 }
+
+func TestMap_Handler(t *testing.T) {
+	handler := example.ErrMap.Handler()
+	for err, want := range example.ErrMap {
+		got := handler(err)
+		if got == nil || got == want {
+			t.Fatalf("expected a copy of %v, got %p", want, got)
+		}
+		if got.Error() != want.Error() || got.Label() != want.Label() {
+			t.Errorf("expected %v, got %v", want, got)
+		}
+	}
+	if got := handler(errors.New("unhandled error")); got != nil {
+		t.Errorf("expected nil, got %v", got)
+	}
+}
+
+func TestMap_Handle_doesNotModifyTemplates(t *testing.T) {
+	template := oops.New("cache key not found", oops.Tag(example.NotFound.Error)).(*oops.Error)
+	errMap := oops.Map{example.RedisCacheMissed: template}
+	first := errMap.Handle(example.RedisCacheMissed).(*oops.Error)
+	second := errMap.Handle(example.RedisCacheMissed).(*oops.Error)
+	if first == template || second == template || first == second {
+		t.Fatal("expected distinct copies of the template")
+	}
+	if causes := template.Causes(); len(causes) != 0 {
+		t.Errorf("expected no causes on the template, got %v", causes)
+	}
+	if causes := second.Causes(); len(causes) != 1 || causes[0] != example.RedisCacheMissed {
+		t.Errorf("expected a single cause, got %v", causes)
+	}
+}
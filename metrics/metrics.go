@@ -0,0 +1,180 @@
+// Package metrics counts the errors created and handled by the oops package per [oops.Label] and severity,
+// and exports the counts through [expvar] and in the Prometheus text exposition format.
+//
+// Labels must be tracked using [Recorder.Track] to be counted under their own name; any other label is counted
+// as [oops.Untagged], which keeps the cardinality of the exported series bounded:
+//
+//	recorder := metrics.New()
+//	recorder.Track(example.NotFound.Error, "resource_not_found", metrics.Warning)
+//	defer recorder.Install()()
+//	http.Handle("/metrics", recorder)
+package metrics
+
+import (
+	"expvar"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/piteego/oops"
+)
+
+// Severity classifies how bad the errors of a [oops.Label] are.
+type Severity string
+
+const (
+	Info     Severity = "info"
+	Warning  Severity = "warning"
+	Error    Severity = "error"
+	Critical Severity = "critical"
+)
+
+// UntaggedName is the name errors with an untracked [oops.Label] are counted under.
+const UntaggedName = "untagged"
+
+type counter struct {
+	name     string
+	severity Severity
+	created  atomic.Uint64
+	handled  atomic.Uint64
+}
+
+// Recorder counts created and handled errors by label. It is safe for concurrent use.
+type Recorder struct {
+	mu       sync.RWMutex
+	counters map[oops.Label]*counter
+}
+
+// New creates a *[Recorder] tracking the labels defined by the oops package, including the labels of the
+// [oops.Stdlib] handlers.
+func New() *Recorder {
+	r := &Recorder{counters: make(map[oops.Label]*counter)}
+	r.Track(oops.Untagged, UntaggedName, Error)
+	r.Track(oops.Panicked, "panicked", Critical)
+	r.Track(oops.Canceled, "canceled", Info)
+	r.Track(oops.DeadlineExceeded, "deadline_exceeded", Warning)
+	r.Track(oops.NotFound, "not_found", Info)
+	r.Track(oops.AlreadyExists, "already_exists", Info)
+	r.Track(oops.PermissionDenied, "permission_denied", Warning)
+	r.Track(oops.InvalidInput, "invalid_input", Info)
+	r.Track(oops.FailedPrecondition, "failed_precondition", Warning)
+	r.Track(oops.Timeout, "timeout", Error)
+	r.Track(oops.Unavailable, "unavailable", Error)
+	r.Track(oops.Aborted, "aborted", Warning)
+	return r
+}
+
+// Track counts the errors of the given [oops.Label] under name and severity.
+// Tracking an already tracked label renames it, but keeps its counts.
+// Names identify the exported series, so it panics if name is already used by another label,
+// including [UntaggedName].
+func (r *Recorder) Track(label oops.Label, name string, severity Severity) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for other, c := range r.counters {
+		if c.name == name && other != label {
+			panic(fmt.Sprintf("metrics: name %q is already used by the label %q", name, other))
+		}
+	}
+	if c, exists := r.counters[label]; exists {
+		c.name, c.severity = name, severity
+		return
+	}
+	r.counters[label] = &counter{name: name, severity: severity}
+}
+
+func (r *Recorder) counter(label oops.Label) *counter {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if c, exists := r.counters[label]; exists {
+		return c
+	}
+	return r.counters[oops.Untagged]
+}
+
+// Created counts an error created by [oops.New].
+func (r *Recorder) Created(err *oops.Error) { r.counter(err.Label()).created.Add(1) }
+
+// Handled counts an error converted by a handler, by the label of the result.
+func (r *Recorder) Handled(_ error, result *oops.Error) { r.counter(result.Label()).handled.Add(1) }
+
+// Install registers the *[Recorder] as global [oops.OnNew] and [oops.OnHandle] hooks.
+// The returned function uninstalls it.
+func (r *Recorder) Install() (uninstall func()) {
+	return r.install(oops.OnNew, oops.OnHandle)
+}
+
+// InstallOn registers the *[Recorder] on scoped [oops.Hooks]. The returned function uninstalls it.
+func (r *Recorder) InstallOn(hooks *oops.Hooks) (uninstall func()) {
+	return r.install(hooks.OnNew, hooks.OnHandle)
+}
+
+func (r *Recorder) install(onNew func(func(*oops.Error)) func(), onHandle func(func(error, *oops.Error)) func()) func() {
+	unregisterNew, unregisterHandle := onNew(r.Created), onHandle(r.Handled)
+	return func() {
+		unregisterNew()
+		unregisterHandle()
+	}
+}
+
+// Sample is the counts of a tracked label.
+type Sample struct {
+	Name     string   `json:"name"`
+	Severity Severity `json:"severity"`
+	Created  uint64   `json:"created"`
+	Handled  uint64   `json:"handled"`
+}
+
+// Snapshot returns the counts of all tracked labels, sorted by name.
+func (r *Recorder) Snapshot() []Sample {
+	r.mu.RLock()
+	samples := make([]Sample, 0, len(r.counters))
+	for _, c := range r.counters {
+		samples = append(samples, Sample{c.name, c.severity, c.created.Load(), c.handled.Load()})
+	}
+	r.mu.RUnlock()
+	sort.Slice(samples, func(i, j int) bool { return samples[i].Name < samples[j].Name })
+	return samples
+}
+
+// Publish exports the [Recorder.Snapshot] as an [expvar] variable with the given name.
+// Like [expvar.Publish], it panics if the name is already in use.
+func (r *Recorder) Publish(name string) {
+	expvar.Publish(name, expvar.Func(func() any { return r.Snapshot() }))
+}
+
+// WritePrometheus writes the counts in the Prometheus text exposition format.
+func (r *Recorder) WritePrometheus(w io.Writer) error {
+	samples := r.Snapshot()
+	metrics := []struct {
+		name, help string
+		value      func(Sample) uint64
+	}{
+		{"oops_errors_created_total", "Errors created, by label and severity.", func(s Sample) uint64 { return s.Created }},
+		{"oops_errors_handled_total", "Errors converted by handlers, by resulting label and severity.", func(s Sample) uint64 { return s.Handled }},
+	}
+	var b strings.Builder
+	for _, m := range metrics {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s counter\n", m.name, m.help, m.name)
+		for _, s := range samples {
+			fmt.Fprintf(&b, "%s{label=\"%s\",severity=\"%s\"} %d\n", m.name, escape(s.Name), escape(string(s.Severity)), m.value(s))
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// ServeHTTP implements [http.Handler], serving the counts in the Prometheus text exposition format.
+func (r *Recorder) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_ = r.WritePrometheus(w)
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// escape escapes a Prometheus label value.
+func escape(value string) string { return escaper.Replace(value) }
@@ -0,0 +1,132 @@
+package metrics_test
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/piteego/oops"
+	"github.com/piteego/oops/example"
+	"github.com/piteego/oops/metrics"
+)
+
+func TestRecorder(t *testing.T) {
+	recorder := metrics.New()
+	recorder.Track(example.NotFound.Error, "resource_not_found", metrics.Warning)
+	recorder.Track(example.Internal.Error, `internal "error"`, metrics.Error)
+	t.Cleanup(recorder.Install())
+
+	_ = oops.New("user not found", oops.Tag(example.NotFound.Error))
+	_ = oops.New("failed to process", oops.Tag(example.Internal.Error))
+	_ = oops.New("not tracked", oops.Tag(example.Forbidden.Error))
+	_ = oops.New("untagged")
+	_ = oops.Handle(example.GormErrRecordNotFound, example.HandleRepoErr("user"))
+	_ = oops.Handle(fs.ErrNotExist, oops.Stdlib()...)
+
+	rec := httptest.NewRecorder()
+	recorder.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if got := rec.Header().Get("Content-Type"); !strings.HasPrefix(got, "text/plain; version=0.0.4") {
+		t.Errorf("unexpected content type %q", got)
+	}
+	want := `# HELP oops_errors_created_total Errors created, by label and severity.
+# TYPE oops_errors_created_total counter
+oops_errors_created_total{label="aborted",severity="warning"} 0
+oops_errors_created_total{label="already_exists",severity="info"} 0
+oops_errors_created_total{label="canceled",severity="info"} 0
+oops_errors_created_total{label="deadline_exceeded",severity="warning"} 0
+oops_errors_created_total{label="failed_precondition",severity="warning"} 0
+oops_errors_created_total{label="internal \"error\"",severity="error"} 1
+oops_errors_created_total{label="invalid_input",severity="info"} 0
+oops_errors_created_total{label="not_found",severity="info"} 1
+oops_errors_created_total{label="panicked",severity="critical"} 0
+oops_errors_created_total{label="permission_denied",severity="warning"} 0
+oops_errors_created_total{label="resource_not_found",severity="warning"} 2
+oops_errors_created_total{label="timeout",severity="error"} 0
+oops_errors_created_total{label="unavailable",severity="error"} 0
+oops_errors_created_total{label="untagged",severity="error"} 2
+# HELP oops_errors_handled_total Errors converted by handlers, by resulting label and severity.
+# TYPE oops_errors_handled_total counter
+oops_errors_handled_total{label="aborted",severity="warning"} 0
+oops_errors_handled_total{label="already_exists",severity="info"} 0
+oops_errors_handled_total{label="canceled",severity="info"} 0
+oops_errors_handled_total{label="deadline_exceeded",severity="warning"} 0
+oops_errors_handled_total{label="failed_precondition",severity="warning"} 0
+oops_errors_handled_total{label="internal \"error\"",severity="error"} 0
+oops_errors_handled_total{label="invalid_input",severity="info"} 0
+oops_errors_handled_total{label="not_found",severity="info"} 1
+oops_errors_handled_total{label="panicked",severity="critical"} 0
+oops_errors_handled_total{label="permission_denied",severity="warning"} 0
+oops_errors_handled_total{label="resource_not_found",severity="warning"} 1
+oops_errors_handled_total{label="timeout",severity="error"} 0
+oops_errors_handled_total{label="unavailable",severity="error"} 0
+oops_errors_handled_total{label="untagged",severity="error"} 0
+`
+	if got := rec.Body.String(); got != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, got)
+	}
+}
+
+func TestRecorder_Track_duplicated(t *testing.T) {
+	recorder := metrics.New()
+	recorder.Track(example.NotFound.Error, "resource_not_found", metrics.Warning)
+	recorder.Track(example.NotFound.Error, "resource_not_found", metrics.Info)
+	for _, name := range []string{"resource_not_found", metrics.UntaggedName} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected a panic when tracking another label as %q", name)
+				}
+			}()
+			recorder.Track(example.Forbidden.Error, name, metrics.Error)
+		})
+	}
+	var series int
+	for _, s := range recorder.Snapshot() {
+		if s.Name == "resource_not_found" {
+			series++
+		}
+	}
+	if series != 1 {
+		t.Errorf("expected a single not_found series, got %d", series)
+	}
+}
+
+func TestRecorder_InstallOn(t *testing.T) {
+	recorder := metrics.New()
+	var hooks oops.Hooks
+	uninstall := recorder.InstallOn(&hooks)
+	ctx := oops.ContextWithHooks(context.Background(), &hooks)
+	_ = oops.HandleContext(ctx, context.Canceled)
+	_ = oops.NewCtx(ctx, "untagged")
+	uninstall()
+	_ = oops.NewCtx(ctx, "after uninstall")
+	for _, s := range recorder.Snapshot() {
+		var created, handled uint64
+		switch s.Name {
+		case "canceled":
+			handled = 1
+		case metrics.UntaggedName:
+			created = 1
+		}
+		if s.Created != created || s.Handled != handled {
+			t.Errorf("expected %s to be created %d and handled %d times, got %+v", s.Name, created, handled, s)
+		}
+	}
+}
+
+func TestRecorder_Publish(t *testing.T) {
+	recorder := metrics.New()
+	recorder.Publish("oops_test_errors")
+	var samples []metrics.Sample
+	if err := json.Unmarshal([]byte(expvar.Get("oops_test_errors").String()), &samples); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(samples) != 12 || samples[0].Name != "aborted" {
+		t.Errorf("unexpected samples %+v", samples)
+	}
+}
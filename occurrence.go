@@ -0,0 +1,146 @@
+package oops
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Occurrence tells when and where an *[Error] was created. It is recorded while [TrackOccurrences] is active.
+type Occurrence struct {
+	Time time.Time // in UTC, as given by the clock, see [SetClock]
+	Host string
+	PID  int
+}
+
+// Hop records a handler or a layer that converted an error into an *[Error], and when.
+type Hop struct {
+	Name string    `json:"name"` // e.g. "example.HandleRepoErr", or the name given to [Via]
+	Time time.Time `json:"time"`
+}
+
+// String returns the hop as "name at time", the time in RFC 3339 format.
+func (h Hop) String() string { return h.Name + " at " + h.Time.Format(time.RFC3339Nano) }
+
+var tracking atomic.Int32
+
+// TrackOccurrences starts recording the [Occurrence] of every *[Error] created, and a [Hop] for every error
+// converted by [Handle], [HandleContext] or a [Map]. The hops of an error converted from an error wrapping an *[Error]
+// start with the hops of the latter, so the hops of the outermost error tell the layers it went through, in order.
+// Tracking is off by default, so formatting and serialization are unchanged unless it is enabled.
+// The returned function stops tracking, unless another call of TrackOccurrences is still active:
+//
+//	t.Cleanup(oops.TrackOccurrences())
+func TrackOccurrences() (untrack func()) {
+	tracking.Add(1)
+	var once sync.Once
+	return func() { once.Do(func() { tracking.Add(-1) }) }
+}
+
+func tracked() bool { return tracking.Load() > 0 }
+
+var clock atomic.Pointer[func() time.Time]
+
+// SetClock replaces the clock giving the time of occurrences, hops and instance IDs, e.g. with a fixed time in tests.
+// A nil clock restores [time.Now]. The returned function restores the previous clock.
+// Replacing or restoring the clock resets the ordering of [NewID], so the next IDs encode the time of the new clock.
+func SetClock(now func() time.Time) (restore func()) {
+	var fn *func() time.Time
+	if now != nil {
+		fn = &now
+	}
+	previous := clock.Swap(fn)
+	resetIDs()
+	return func() {
+		clock.Store(previous)
+		resetIDs()
+	}
+}
+
+// now returns the time of the clock in UTC, without monotonic clock reading.
+func now() time.Time {
+	if fn := clock.Load(); fn != nil {
+		return (*fn)().UTC()
+	}
+	return time.Now().UTC()
+}
+
+// process is the host and process part of the occurrences, which never changes.
+var process = sync.OnceValue(func() Occurrence {
+	host, _ := os.Hostname()
+	return Occurrence{Host: host, PID: os.Getpid()}
+})
+
+// stamp records the occurrence of the error if occurrences are tracked.
+func (err *Error) stamp() {
+	if tracked() {
+		err.occurrence = process()
+		err.occurrence.Time = now()
+	}
+}
+
+// handledBy records that the handler converted original into err: err keeps the instance ID of the *Error
+// wrapped by original, if any, and, if occurrences are tracked, gets a hop after the hops of that *Error.
+// The handler is a function or its name.
+func (err *Error) handledBy(original error, handler any) {
+	var cause *Error
+	if errors.As(original, &cause) && cause.id != "" {
+		err.id = cause.id
+	}
+	if !tracked() {
+		return
+	}
+	var hops []Hop
+	if cause != nil {
+		hops = append(hops, cause.hops...)
+	}
+	name, ok := handler.(string)
+	if !ok {
+		name = funcName(handler)
+	}
+	err.hops = append(append(hops, err.hops...), Hop{Name: name, Time: now()})
+}
+
+// funcName returns the name of the function fn, without its import path nor closure and method value suffixes,
+// e.g. "example.HandleRepoErr" for a closure returned by HandleRepoErr.
+func funcName(fn any) string {
+	f := runtime.FuncForPC(reflect.ValueOf(fn).Pointer())
+	if f == nil {
+		return "unknown"
+	}
+	name := strings.TrimSuffix(f.Name(), "-fm")
+	name = name[strings.LastIndexByte(name, '/')+1:]
+	for {
+		i := strings.LastIndexByte(name, '.')
+		suffix := strings.TrimPrefix(name[i+1:], "func")
+		if i < 0 || suffix == "" || strings.Trim(suffix, "0123456789") != "" {
+			return name
+		}
+		name = name[:i]
+	}
+}
+
+// Via records a [Hop] named after a layer the *[Error] goes through, e.g. Via("billing"),
+// whether occurrences are tracked or not.
+func Via(name string) ErrorOption {
+	return func(err *Error) {
+		err.hops = append(err.hops[:len(err.hops):len(err.hops)], Hop{Name: name, Time: now()})
+	}
+}
+
+// Occurrence returns when and where the error was created, or the zero Occurrence if it was not tracked,
+// see [TrackOccurrences].
+func (err *Error) Occurrence() Occurrence { return err.occurrence }
+
+// Hops returns a copy of the handlers and layers that converted errors into the error, in order, see [TrackOccurrences] and [Via].
+func (err *Error) Hops() []Hop {
+	if len(err.hops) == 0 {
+		return nil
+	}
+	return append([]Hop(nil), err.hops...)
+}
@@ -0,0 +1,154 @@
+package oops_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/piteego/oops"
+	"github.com/piteego/oops/example"
+)
+
+// tick sets a clock starting at 2026-01-02T03:04:05Z and advancing by a second at every call.
+func tick(t *testing.T) {
+	t.Helper()
+	current := time.Date(2026, 1, 2, 3, 4, 4, 0, time.UTC)
+	t.Cleanup(oops.SetClock(func() time.Time {
+		current = current.Add(time.Second)
+		return current
+	}))
+}
+
+func at(second int) time.Time { return time.Date(2026, 1, 2, 3, 4, second, 0, time.UTC) }
+
+func TestTrackOccurrences(t *testing.T) {
+	tick(t)
+	untracked := oops.New("failed").(*oops.Error)
+	if untracked.Occurrence() != (oops.Occurrence{}) || untracked.Hops() != nil {
+		t.Errorf("expected no occurrence nor hops while not tracking, got %+v", untracked)
+	}
+	if strings.Contains(fmt.Sprintf("%+v", untracked), "time:") {
+		t.Errorf("expected no time while not tracking")
+	}
+
+	t.Cleanup(oops.TrackOccurrences())
+	err := oops.New("failed").(*oops.Error)
+	host, _ := os.Hostname()
+	want := oops.Occurrence{Time: at(5), Host: host, PID: os.Getpid()}
+	if err.Occurrence() != want {
+		t.Errorf("expected occurrence %+v, got %+v", want, err.Occurrence())
+	}
+	verbose := fmt.Sprintf("%+v", err)
+	for _, line := range []string{"time: 2026-01-02T03:04:05Z", "host: " + host, fmt.Sprintf("pid: %d", os.Getpid())} {
+		if !strings.Contains(verbose, line) {
+			t.Errorf("expected %q in:\n%s", line, verbose)
+		}
+	}
+	logged := map[string]any{}
+	for _, attr := range err.LogValue().Group() {
+		logged[attr.Key] = attr.Value.Any()
+	}
+	if logged["time"] != at(5) || logged["pid"] != int64(os.Getpid()) {
+		t.Errorf("expected the occurrence to be logged, got %v", logged)
+	}
+}
+
+func TestTrackOccurrences_untrack(t *testing.T) {
+	untrack := oops.TrackOccurrences()
+	other := oops.TrackOccurrences()
+	untrack()
+	untrack()
+	if oops.New("failed").(*oops.Error).Occurrence().Time.IsZero() {
+		t.Errorf("expected tracking while another TrackOccurrences is active")
+	}
+	other()
+	if !oops.New("failed").(*oops.Error).Occurrence().Time.IsZero() {
+		t.Errorf("expected no tracking once every TrackOccurrences is undone")
+	}
+}
+
+func TestError_Hops(t *testing.T) {
+	tick(t)
+	t.Cleanup(oops.TrackOccurrences())
+	repoErr := oops.Handle(example.GormErrRecordNotFound, example.HandleRepoErr("user"))
+	serviceErr := oops.Handle(fmt.Errorf("get profile: %w", repoErr), oops.Fallback(example.Internal.Error, "profile unavailable"))
+	want := []oops.Hop{{Name: "example.HandleRepoErr", Time: at(6)}, {Name: "oops.Fallback", Time: at(8)}}
+	if got := serviceErr.(*oops.Error).Hops(); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected hops %v, got %v", want, got)
+	}
+
+	t.Run("map", func(t *testing.T) {
+		tick(t)
+		err := example.ErrMap.Handle(example.RedisCacheMissed).(*oops.Error)
+		if got := err.Occurrence().Time; !got.Equal(at(5)) {
+			t.Errorf("expected the copy of the template to be stamped when handled, got %v", got)
+		}
+		if want := []oops.Hop{{Name: "oops.Map.Handle", Time: at(6)}}; !reflect.DeepEqual(err.Hops(), want) {
+			t.Errorf("expected hops %v, got %v", want, err.Hops())
+		}
+		handled := oops.Handle(example.RedisCacheMissed, example.ErrMap.Handler()).(*oops.Error)
+		if hops := handled.Hops(); len(hops) != 1 || hops[0].Name != "oops.Map.Handler" {
+			t.Errorf("expected a hop of the map handler, got %v", hops)
+		}
+	})
+
+	t.Run("context", func(t *testing.T) {
+		err := oops.HandleContext(context.Background(), fmt.Errorf("query: %w", context.Canceled)).(*oops.Error)
+		if hops := err.Hops(); len(hops) != 1 || hops[0].Name != "oops.handleContextErr" {
+			t.Errorf("expected a hop of the context handler, got %v", hops)
+		}
+	})
+
+	t.Run("method value", func(t *testing.T) {
+		var h hopHandler
+		err := oops.Handle(errors.New("boom"), h.Handle).(*oops.Error)
+		if hops := err.Hops(); len(hops) != 1 || hops[0].Name != "oops_test.hopHandler.Handle" {
+			t.Errorf("expected a hop named after the method, got %v", hops)
+		}
+	})
+}
+
+type hopHandler struct{}
+
+func (hopHandler) Handle(err error) *oops.Error { return oops.New("handled").(*oops.Error) }
+
+func TestVia(t *testing.T) {
+	tick(t)
+	err := oops.New("failed", oops.Via("billing")).(*oops.Error)
+	if want := []oops.Hop{{Name: "billing", Time: at(5)}}; !reflect.DeepEqual(err.Hops(), want) {
+		t.Errorf("expected hops %v, got %v", want, err.Hops())
+	}
+	if got, want := fmt.Sprintf("%+v", err), "failed\n    label: untagged\n    hops:\n        - billing at 2026-01-02T03:04:05Z"; got != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, got)
+	}
+}
+
+func TestOccurrence_serialization(t *testing.T) {
+	tick(t)
+	t.Cleanup(oops.TrackOccurrences())
+	err := oops.Handle(example.GormErrRecordNotFound, example.HandleRepoErr("user")).(*oops.Error)
+	decoded := roundTrip(t, err)
+	if decoded.Occurrence() != err.Occurrence() || !reflect.DeepEqual(decoded.Hops(), err.Hops()) {
+		t.Errorf("expected JSON to keep the occurrence and hops, got %+v", decoded)
+	}
+	data, binErr := err.MarshalBinary()
+	if binErr != nil {
+		t.Fatalf("unexpected error: %v", binErr)
+	}
+	var binDecoded oops.Error
+	if binErr := binDecoded.UnmarshalBinary(data); binErr != nil {
+		t.Fatalf("unexpected error: %v", binErr)
+	}
+	if got, want := fmt.Sprintf("%+v", &binDecoded), fmt.Sprintf("%+v", err); got != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, got)
+	}
+	var v1 oops.Error
+	if binErr := v1.UnmarshalBinary(nestedBinary(3)); binErr != nil {
+		t.Errorf("expected version 1 to be decoded, got %v", binErr)
+	}
+}
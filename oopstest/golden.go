@@ -0,0 +1,127 @@
+package oopstest
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/piteego/oops"
+)
+
+var update = flag.Bool("oopstest.update", false, "update the golden files of oopstest.AssertGolden")
+
+// AssertGolden asserts that the rendered err tree (see [Render]) matches the content of the golden file at path.
+// Run the tests with the -oopstest.update flag to write the golden files instead.
+func AssertGolden(t testing.TB, err error, path string) {
+	t.Helper()
+	got := Render(err)
+	if *update {
+		if mkErr := os.MkdirAll(filepath.Dir(path), 0o755); mkErr != nil {
+			t.Fatalf("creating golden file directory: %v", mkErr)
+		}
+		if writeErr := os.WriteFile(path, []byte(got), 0o644); writeErr != nil {
+			t.Fatalf("updating golden file: %v", writeErr)
+		}
+		return
+	}
+	want, readErr := os.ReadFile(path)
+	if readErr != nil {
+		t.Fatalf("reading golden file (run with -oopstest.update to create it): %v", readErr)
+	}
+	if got != string(want) {
+		t.Errorf("error tree does not match golden file %s (-want +got):\n%s", path, diff(string(want), got))
+	}
+}
+
+// maxDepth bounds the nesting of rendered errors.
+const maxDepth = 32
+
+// Render returns a deterministic, indented text representation of the err tree:
+// the message, label, message template and parameters, attributes and causes of each *[oops.Error], and the message and type of other errors.
+// Volatile data, such as captured frames, is left out so the result can be compared to golden files.
+func Render(err error) string {
+	var b strings.Builder
+	render(&b, err, "", 0)
+	return b.String()
+}
+
+func render(b *strings.Builder, err error, indent string, depth int) {
+	if err == nil {
+		fmt.Fprintf(b, "%s<nil>\n", indent)
+		return
+	}
+	fmt.Fprintf(b, "%smessage: %s\n", indent, err.Error())
+	oopsErr, ok := err.(*oops.Error)
+	if !ok {
+		fmt.Fprintf(b, "%stype: %T\n", indent, err)
+		return
+	}
+	if label := oopsErr.Label(); label != nil {
+		fmt.Fprintf(b, "%slabel: %s\n", indent, label.Error())
+	}
+	if params := oopsErr.Params(); len(params) > 0 {
+		fmt.Fprintf(b, "%stemplate: %s\n", indent, oopsErr.Template())
+		fmt.Fprintf(b, "%sparams:\n", indent)
+		for _, param := range params {
+			fmt.Fprintf(b, "%s  %s: %v\n", indent, param.Name, param.Value)
+		}
+	}
+	if attrs := oopsErr.Attrs(); len(attrs) > 0 {
+		fmt.Fprintf(b, "%sattrs:\n", indent)
+		for _, attr := range attrs {
+			fmt.Fprintf(b, "%s  %s: %v\n", indent, attr.Key, attr.Value)
+		}
+	}
+	causes := oopsErr.Causes()
+	if len(causes) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "%scauses:\n", indent)
+	for _, cause := range causes {
+		if depth >= maxDepth {
+			fmt.Fprintf(b, "%s  - ...\n", indent)
+			return
+		}
+		var nested strings.Builder
+		render(&nested, cause, indent+"    ", depth+1)
+		fmt.Fprintf(b, "%s  - %s", indent, strings.TrimPrefix(nested.String(), indent+"    "))
+	}
+}
+
+// diff returns a line based diff of want and got, prefixing removed lines with "-" and added lines with "+".
+func diff(want, got string) string {
+	a, b := strings.Split(want, "\n"), strings.Split(got, "\n")
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	var out strings.Builder
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			fmt.Fprintf(&out, " %s\n", a[i])
+			i, j = i+1, j+1
+		case j < len(b) && (i == len(a) || lcs[i][j+1] >= lcs[i+1][j]):
+			fmt.Fprintf(&out, "+%s\n", b[j])
+			j++
+		default:
+			fmt.Fprintf(&out, "-%s\n", a[i])
+			i++
+		}
+	}
+	return out.String()
+}
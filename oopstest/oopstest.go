@@ -0,0 +1,80 @@
+// Package oopstest provides assertion helpers for testing errors labeled by the oops package.
+// It depends on the standard library only:
+//
+//	func TestFindUser(t *testing.T) {
+//		_, err := repo.FindUser(42)
+//		oopstest.AssertLabel(t, err, example.NotFound.Error)
+//		oopstest.AssertCause(t, err, example.GormErrRecordNotFound)
+//		oopstest.AssertAttr(t, err, "user_id", 42)
+//		oopstest.AssertGolden(t, err, "testdata/find_user.golden")
+//	}
+package oopstest
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/piteego/oops"
+)
+
+// asOops returns the outermost *[oops.Error] in the err tree, failing the test if there is none.
+func asOops(t testing.TB, err error) (*oops.Error, bool) {
+	t.Helper()
+	var oopsErr *oops.Error
+	if !errors.As(err, &oopsErr) {
+		t.Errorf("expected an *oops.Error, got %T: %v", err, err)
+		return nil, false
+	}
+	return oopsErr, true
+}
+
+// AssertLabel asserts that the outermost *[oops.Error] in the err tree is labeled with label.
+func AssertLabel(t testing.TB, err error, label oops.Label) {
+	t.Helper()
+	oopsErr, ok := asOops(t, err)
+	if !ok {
+		return
+	}
+	if oopsErr.Label() != label {
+		t.Errorf("unexpected label\n  want: %v\n   got: %v\nerror:\n%s", label, oopsErr.Label(), Render(err))
+	}
+}
+
+// AssertCause asserts that target is found in the err tree using [errors.Is].
+func AssertCause(t testing.TB, err error, target error) {
+	t.Helper()
+	if !errors.Is(err, target) {
+		t.Errorf("expected cause %q (%T) not found\nerror:\n%s", target, target, Render(err))
+	}
+}
+
+// AssertMessage asserts that the message of err is msg.
+func AssertMessage(t testing.TB, err error, msg string) {
+	t.Helper()
+	if err == nil {
+		t.Errorf("expected error with message %q, got nil", msg)
+		return
+	}
+	if err.Error() != msg {
+		t.Errorf("unexpected message\n  want: %q\n   got: %q", msg, err.Error())
+	}
+}
+
+// AssertAttr asserts that the outermost *[oops.Error] in the err tree has an attribute with the given key,
+// whose value is deeply equal to want.
+func AssertAttr(t testing.TB, err error, key string, want any) {
+	t.Helper()
+	oopsErr, ok := asOops(t, err)
+	if !ok {
+		return
+	}
+	got, exists := oopsErr.Attr(key)
+	if !exists {
+		t.Errorf("expected attribute %q not found\nerror:\n%s", key, Render(err))
+		return
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected attribute %q\n  want: %#v\n   got: %#v", key, want, got)
+	}
+}
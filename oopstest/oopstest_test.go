@@ -0,0 +1,96 @@
+package oopstest_test
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/piteego/oops"
+	"github.com/piteego/oops/example"
+	"github.com/piteego/oops/oopstest"
+)
+
+// recorder is a testing.TB recording failures instead of failing the test.
+type recorder struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Fatalf(format string, args ...any) { r.Errorf(format, args...) }
+
+func sample() error {
+	inner := oops.New("user not found",
+		oops.Tag(example.NotFound.Error),
+		oops.With("user_id", 42),
+		oops.Because(example.GormErrRecordNotFound),
+	)
+	return oops.New("failed to process", oops.Tag(example.Internal.Error), oops.Because(inner))
+}
+
+func TestAsserts(t *testing.T) {
+	err := sample()
+	inner := err.(*oops.Error).Unwrap()[0]
+	testCases := []struct {
+		name   string
+		assert func(t testing.TB)
+		fail   string
+	}{
+		{"label", func(t testing.TB) { oopstest.AssertLabel(t, err, example.Internal.Error) }, ""},
+		{"wrong label", func(t testing.TB) { oopstest.AssertLabel(t, err, example.NotFound.Error) }, "unexpected label"},
+		{"label of non oops error", func(t testing.TB) { oopstest.AssertLabel(t, errors.New("x"), oops.Untagged) }, "expected an *oops.Error"},
+		{"cause", func(t testing.TB) { oopstest.AssertCause(t, err, example.GormErrRecordNotFound) }, ""},
+		{"missing cause", func(t testing.TB) { oopstest.AssertCause(t, err, example.RedisCacheMissed) }, "expected cause"},
+		{"message", func(t testing.TB) { oopstest.AssertMessage(t, err, "failed to process") }, ""},
+		{"wrong message", func(t testing.TB) { oopstest.AssertMessage(t, err, "oops") }, "unexpected message"},
+		{"nil message", func(t testing.TB) { oopstest.AssertMessage(t, nil, "oops") }, "got nil"},
+		{"attr", func(t testing.TB) { oopstest.AssertAttr(t, inner, "user_id", 42) }, ""},
+		{"wrong attr", func(t testing.TB) { oopstest.AssertAttr(t, inner, "user_id", "42") }, "unexpected attribute"},
+		{"missing attr", func(t testing.TB) { oopstest.AssertAttr(t, err, "user_id", 42) }, "not found"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &recorder{TB: t}
+			tc.assert(r)
+			switch {
+			case tc.fail == "" && len(r.failures) > 0:
+				t.Errorf("expected no failure, got %q", r.failures)
+			case tc.fail != "" && (len(r.failures) != 1 || !strings.Contains(r.failures[0], tc.fail)):
+				t.Errorf("expected a failure containing %q, got %q", tc.fail, r.failures)
+			}
+		})
+	}
+}
+
+func TestAssertGolden(t *testing.T) {
+	oopstest.AssertGolden(t, sample(), "testdata/sample.golden")
+	if flag.Lookup("oopstest.update").Value.String() == "true" {
+		return
+	}
+
+	r := &recorder{TB: t}
+	oopstest.AssertGolden(r, oops.New("failed to process", oops.Tag(example.Internal.Error)), "testdata/sample.golden")
+	want := strings.Join([]string{
+		" message: failed to process",
+		" label: something went wrong",
+		"-causes:",
+		"-  - message: user not found",
+		"-    label: resource not found",
+		"-    attrs:",
+		"-      user_id: 42",
+		"-    causes:",
+		"-      - message: gorm record not found",
+		"-        type: *errors.errorString",
+		" ",
+	}, "\n")
+	if len(r.failures) != 1 || !strings.HasSuffix(r.failures[0], want+"\n") {
+		t.Errorf("expected a readable diff, got %q", r.failures)
+	}
+}
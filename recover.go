@@ -0,0 +1,61 @@
+package oops
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Panicked label serves as a default for errors created from a recovered panic
+// by [Recover] and [Safe] when no other [Label] is tagged.
+var Panicked Label = errors.New("panicked")
+
+// PanicValue is the cause of a recovered panic whose value is not an error.
+// Use [errors.As] to retrieve the original panic value.
+type PanicValue struct {
+	Value any
+}
+
+// Error implements golang's builtin error interface.
+func (p *PanicValue) Error() string { return fmt.Sprint(p.Value) }
+
+// Recover converts a panic into an *[Error] and stores it in errp.
+// It must be called directly by defer, typically with a named return error:
+//
+//	defer oops.Recover(&err, oops.Tag(example.Internal.Error))
+//
+// The panic value is kept as a cause of the returned *[Error] ([PanicValue] if it is not an error),
+// and the goroutine stack at the point of panic is available through [Error.Frames].
+// If no [Label] is tagged using options, the error is labeled as [Panicked].
+// A nil errp re-panics with the created *[Error].
+func Recover(errp *error, options ...ErrorOption) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	err := fromPanic(r, options)
+	if errp == nil {
+		panic(err)
+	}
+	*errp = err
+}
+
+// Safe calls fn and converts any panic raised by it into an *[Error] as [Recover] does.
+// Otherwise, it returns the error returned by fn.
+func Safe(fn func() error, options ...ErrorOption) (err error) {
+	defer Recover(&err, options...)
+	return fn()
+}
+
+func fromPanic(r any, options []ErrorOption) error {
+	cause, ok := r.(error)
+	if !ok {
+		cause = &PanicValue{Value: r}
+	}
+	frames := panicking(callers(2))
+	options = append(options[:len(options):len(options)],
+		Tag(Panicked),
+		Because(cause),
+		func(err *Error) { err.frames = frames },
+	)
+	return New("panic: "+cause.Error(), options...)
+}
@@ -0,0 +1,95 @@
+package oops_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/piteego/oops"
+	"github.com/piteego/oops/example"
+)
+
+func panicWith(v any) func() error {
+	return func() error {
+		panic(v)
+	}
+}
+
+func TestRecover(t *testing.T) {
+	cause := errors.New("cause error")
+	testCases := []struct {
+		name  string
+		value any
+		label oops.Label
+	}{
+		{"error value", cause, example.Internal.Error},
+		{"string value", "boom", example.Internal.Error},
+		{"untagged", 42, nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var options []oops.ErrorOption
+			if tc.label != nil {
+				options = append(options, oops.Tag(tc.label))
+			}
+			got := func() (err error) {
+				defer oops.Recover(&err, options...)
+				return panicWith(tc.value)()
+			}()
+			var oopsErr *oops.Error
+			if !errors.As(got, &oopsErr) {
+				t.Fatalf("expected *oops.Error, got %T", got)
+			}
+			want := tc.label
+			if want == nil {
+				want = oops.Panicked
+			}
+			if !errors.Is(got, want) {
+				t.Errorf("expected error labeled %q, got %q", want, oopsErr.Label())
+			}
+			if err, ok := tc.value.(error); ok && !errors.Is(got, err) {
+				t.Errorf("expected panic error to be a cause, got %q", oopsErr.Unwrap())
+			}
+			var pv *oops.PanicValue
+			if _, ok := tc.value.(error); !ok && (!errors.As(got, &pv) || pv.Value != tc.value) {
+				t.Errorf("expected panic value %v to be preserved, got %v", tc.value, pv)
+			}
+			frames := oopsErr.Frames()
+			if len(frames) == 0 {
+				t.Fatalf("expected captured frames, got none")
+			}
+			if !strings.Contains(frames[0].Function, "panicWith") {
+				t.Errorf("expected stack to start at the panicking function, got %s", frames[0])
+			}
+		})
+	}
+}
+
+func TestRecover_NoPanic(t *testing.T) {
+	want := errors.New("returned error")
+	got := func() (err error) {
+		defer oops.Recover(&err)
+		return want
+	}()
+	if got != want {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestSafe(t *testing.T) {
+	if err := oops.Safe(func() error { return nil }); err != nil {
+		t.Errorf("expected nil, got %v", err)
+	}
+	err := oops.Safe(func() error {
+		var m map[string]int
+		m["key"]++
+		return nil
+	}, oops.Tag(example.Internal.Error))
+	if !errors.Is(err, example.Internal.Error) {
+		t.Errorf("expected error labeled %q, got %v", example.Internal.Error, err)
+	}
+	frames := err.(*oops.Error).Frames()
+	if len(frames) == 0 || !strings.HasPrefix(frames[0].Function, "github.com/piteego/oops_test.TestSafe") {
+		t.Errorf("expected stack to start at the panicking function, got %v", frames)
+	}
+}
@@ -0,0 +1,163 @@
+package oops_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/piteego/oops"
+	"github.com/piteego/oops/example"
+)
+
+func TestRedactors(t *testing.T) {
+	testCases := []struct {
+		name     string
+		redactor oops.Redactor
+		in       string
+		want     string
+	}{
+		{"email", oops.RedactEmails, "user john.doe+1@example.com exists", "user [REDACTED] exists"},
+		{"bearer", oops.RedactBearerTokens, "header Authorization: Bearer eyJhbGciOi.J9-x_y rejected", "header Authorization: Bearer [REDACTED] rejected"},
+		{"basic", oops.RedactBearerTokens, "basic dXNlcjpwYXNz", "basic [REDACTED]"},
+		{"dsn", oops.RedactDSNCredentials, `dial "postgres://admin:s3cr3t@db:5432/app" failed`, `dial "postgres://admin:[REDACTED]@db:5432/app" failed`},
+		{"dsn without password", oops.RedactDSNCredentials, "redis://db:6379/0", "redis://db:6379/0"},
+		{"pattern", oops.RedactPattern(regexp.MustCompile(`token=(\w{2})\w*`), "token=${1}…"), "token=abcdef", "token=ab…"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.redactor(tc.in); got != tc.want {
+				t.Errorf("expected %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestRegisterRedactor(t *testing.T) {
+	t.Cleanup(oops.RegisterRedactor(oops.RedactEmails))
+	t.Cleanup(oops.RegisterRedactor(oops.RedactDSNCredentials))
+	cause := errors.New("pq: connect postgres://admin:s3cr3t@db/app: no such host")
+	err := oops.Newf("user {email} not found", "john@example.com",
+		oops.Tag(example.NotFound.Error),
+		oops.With("contact", "jane@example.com"),
+		oops.With("attempts", 3),
+		oops.Sensitive("password", "hunter2"),
+		oops.Because(cause),
+	).(*oops.Error)
+
+	secrets := []string{"john@example.com", "jane@example.com", "hunter2", "s3cr3t"}
+	var buf bytes.Buffer
+	slog.New(slog.NewJSONHandler(&buf, nil)).Error("failed", "err", err)
+	data, _ := json.Marshal(err)
+	outputs := map[string]string{
+		"%v":   fmt.Sprintf("%v", err),
+		"%s":   fmt.Sprintf("%s", err),
+		"%q":   fmt.Sprintf("%q", err),
+		"%+v":  fmt.Sprintf("%+v", err),
+		"json": string(data),
+		"slog": buf.String(),
+	}
+	for name, output := range outputs {
+		for _, secret := range secrets {
+			if strings.Contains(output, secret) {
+				t.Errorf("%s: expected %q to be redacted in %s", name, secret, output)
+			}
+		}
+	}
+	if !strings.Contains(outputs["json"], `"attempts":3`) {
+		t.Errorf("expected non sensitive values to be kept, got %s", outputs["json"])
+	}
+	if err.Error() != "user john@example.com not found" {
+		t.Errorf("expected Error() not to be redacted, got %q", err.Error())
+	}
+	if got, _ := err.Attr("password"); got != "hunter2" {
+		t.Errorf("expected Attr to return the sensitive value, got %v", got)
+	}
+	unredacted := fmt.Sprintf("%+v", err.Unredacted())
+	data, _ = json.Marshal(err.Unredacted())
+	for _, secret := range secrets {
+		if !strings.Contains(unredacted, secret) || !strings.Contains(string(data), secret) {
+			t.Errorf("expected %q in unredacted outputs %s and %s", secret, unredacted, data)
+		}
+	}
+	if !errors.Is(err.Unredacted(), example.NotFound.Error) {
+		t.Errorf("expected unredacted view to unwrap the error")
+	}
+}
+
+func TestRegisterRedactor_template(t *testing.T) {
+	t.Cleanup(oops.RegisterRedactor(oops.RedactEmails))
+	err := oops.New("login failed for bob@example.com in {region}", oops.Bind("region", "eu")).(*oops.Error)
+	var buf bytes.Buffer
+	slog.New(slog.NewJSONHandler(&buf, nil)).Error("failed", "err", err)
+	data, _ := json.Marshal(err)
+	binary, _ := err.MarshalBinary()
+	var decoded oops.Error
+	if binErr := decoded.UnmarshalBinary(binary); binErr != nil {
+		t.Fatalf("unexpected error: %v", binErr)
+	}
+	outputs := map[string]string{
+		"%+v":    fmt.Sprintf("%+v", err),
+		"json":   string(data),
+		"slog":   buf.String(),
+		"binary": string(binary),
+	}
+	for name, output := range outputs {
+		if strings.Contains(output, "bob@example.com") {
+			t.Errorf("%s: expected the template to be redacted in %s", name, output)
+		}
+	}
+	if got, want := decoded.Template(), "login failed for "+oops.Redacted+" in {region}"; got != want {
+		t.Errorf("expected the decoded template %q, got %q", want, got)
+	}
+	if !strings.Contains(fmt.Sprintf("%+v", err.Unredacted()), "template: login failed for bob@example.com in {region}") {
+		t.Errorf("expected the unredacted template, got %+v", err.Unredacted())
+	}
+}
+
+func TestRegisterRedactor_values(t *testing.T) {
+	t.Cleanup(oops.RegisterRedactor(oops.RedactEmails))
+	t.Cleanup(oops.RegisterRedactor(oops.RedactDSNCredentials))
+	type account struct{ Email string }
+	dsn, _ := url.Parse("postgres://bob:secret@db/x")
+	err := oops.New("failed", oops.With("dsn", dsn), oops.With("account", account{Email: "a@b.com"}), oops.With("retries", 3)).(*oops.Error)
+	var buf bytes.Buffer
+	slog.New(slog.NewJSONHandler(&buf, nil)).Error("failed", "err", err)
+	data, jsonErr := json.Marshal(err)
+	if jsonErr != nil {
+		t.Fatalf("unexpected error: %v", jsonErr)
+	}
+	binary, _ := err.MarshalBinary()
+	outputs := map[string]string{
+		"%+v":    fmt.Sprintf("%+v", err),
+		"json":   string(data),
+		"slog":   buf.String(),
+		"binary": string(binary),
+	}
+	for name, output := range outputs {
+		if strings.Contains(output, "secret") || strings.Contains(output, "a@b.com") {
+			t.Errorf("%s: expected the values to be redacted in %s", name, output)
+		}
+	}
+	if !strings.Contains(string(data), `"retries":3`) {
+		t.Errorf("expected numbers to be kept as they are in %s", data)
+	}
+	if verbose := fmt.Sprintf("%+v", err.Unredacted()); !strings.Contains(verbose, "postgres://bob:secret@db/x") {
+		t.Errorf("expected the unredacted value in:\n%s", verbose)
+	}
+}
+
+func TestSensitive_Overwrite(t *testing.T) {
+	err := oops.New("failed", oops.Sensitive("key", "secret"), oops.With("key", "public")).(*oops.Error)
+	if attrs := err.Attrs(); len(attrs) != 1 || attrs[0].Sensitive {
+		t.Errorf("expected With to overwrite the sensitive attribute, got %v", attrs)
+	}
+	if verbose := fmt.Sprintf("%+v", oops.New("failed", oops.Sensitive("key", "secret"))); !strings.Contains(verbose, "key: "+oops.Redacted) {
+		t.Errorf("expected sensitive attribute to be redacted, got %q", verbose)
+	}
+}
@@ -0,0 +1,91 @@
+package oops
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Definition describes a [Label] registered in the catalog of an application.
+type Definition struct {
+	Label       Label
+	Code        string // stable, unique identifier of the Label, e.g. "NOT_FOUND"
+	Description string
+	HTTPStatus  int
+	GRPCCode    int
+}
+
+var registry = struct {
+	sync.RWMutex
+	byLabel map[Label]Definition
+	byCode  map[string]Definition
+	maps    []Map
+}{
+	byLabel: make(map[Label]Definition),
+	byCode:  make(map[string]Definition),
+}
+
+// Register adds label definitions to the registry, typically from an init function as generated by oopsgen.
+// It panics if a definition has a nil [Label], an empty code or a code starting with "oops.", which is reserved
+// (see [RegisterStdlib]), or if its [Label] or code is already registered.
+func Register(defs ...Definition) {
+	registry.Lock()
+	defer registry.Unlock()
+	for _, def := range defs {
+		if def.Label == nil || def.Code == "" || strings.HasPrefix(def.Code, stdlibCodePrefix) {
+			panic(fmt.Sprintf("oops: invalid definition of label %v with code %q", def.Label, def.Code))
+		}
+		if _, exists := registry.byLabel[def.Label]; exists {
+			panic(fmt.Sprintf("oops: label %q registered twice", def.Label))
+		}
+		if _, exists := registry.byCode[def.Code]; exists {
+			panic(fmt.Sprintf("oops: code %q registered twice", def.Code))
+		}
+		registry.byLabel[def.Label] = def
+		registry.byCode[def.Code] = def
+	}
+}
+
+// Lookup returns the [Definition] of a registered [Label].
+func Lookup(label Label) (Definition, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	def, exists := registry.byLabel[label]
+	return def, exists
+}
+
+// LookupCode returns the [Definition] registered with the given code.
+func LookupCode(code string) (Definition, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	def, exists := registry.byCode[code]
+	return def, exists
+}
+
+// Definitions returns all registered definitions, sorted by code.
+func Definitions() []Definition {
+	registry.RLock()
+	defs := make([]Definition, 0, len(registry.byCode))
+	for _, def := range registry.byCode {
+		defs = append(defs, def)
+	}
+	registry.RUnlock()
+	sort.Slice(defs, func(i, j int) bool { return defs[i].Code < defs[j].Code })
+	return defs
+}
+
+// RegisterMap adds maps to the registry, so that tools such as catalog exports can list
+// the *[Error] instances clients may receive. It is typically called from an init function.
+func RegisterMap(maps ...Map) {
+	registry.Lock()
+	defer registry.Unlock()
+	registry.maps = append(registry.maps, maps...)
+}
+
+// Maps returns all registered maps, in registration order.
+func Maps() []Map {
+	registry.RLock()
+	defer registry.RUnlock()
+	return append([]Map(nil), registry.maps...)
+}
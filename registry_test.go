@@ -0,0 +1,69 @@
+package oops_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/piteego/oops"
+)
+
+var (
+	registryTestLabelA oops.Label = errors.New("registry test label a")
+	registryTestLabelB oops.Label = errors.New("registry test label b")
+)
+
+func TestRegister(t *testing.T) {
+	oops.Register(
+		oops.Definition{Label: registryTestLabelB, Code: "REGISTRY_TEST_B", HTTPStatus: 409},
+		oops.Definition{Label: registryTestLabelA, Code: "REGISTRY_TEST_A", Description: "A test label.", HTTPStatus: 404, GRPCCode: 5},
+	)
+	def, ok := oops.Lookup(registryTestLabelA)
+	if !ok || def.Code != "REGISTRY_TEST_A" || def.HTTPStatus != 404 || def.GRPCCode != 5 {
+		t.Errorf("unexpected definition %+v", def)
+	}
+	if def, ok := oops.LookupCode("REGISTRY_TEST_B"); !ok || def.Label != registryTestLabelB {
+		t.Errorf("unexpected definition %+v", def)
+	}
+	if _, ok := oops.Lookup(errors.New("registry test label a")); ok {
+		t.Errorf("expected an unregistered label not to be found")
+	}
+	var codes []string
+	for _, def := range oops.Definitions() {
+		if def.Label == registryTestLabelA || def.Label == registryTestLabelB {
+			codes = append(codes, def.Code)
+		}
+	}
+	if len(codes) != 2 || codes[0] != "REGISTRY_TEST_A" {
+		t.Errorf("expected definitions sorted by code, got %v", codes)
+	}
+	testCases := []struct {
+		name string
+		def  oops.Definition
+	}{
+		{"nil label", oops.Definition{Code: "REGISTRY_TEST_NIL"}},
+		{"empty code", oops.Definition{Label: errors.New("empty code")}},
+		{"reserved code", oops.Definition{Label: errors.New("reserved code"), Code: "oops.NOT_FOUND"}},
+		{"duplicated label", oops.Definition{Label: registryTestLabelA, Code: "REGISTRY_TEST_C"}},
+		{"duplicated code", oops.Definition{Label: errors.New("duplicated code"), Code: "REGISTRY_TEST_A"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected Register to panic")
+				}
+			}()
+			oops.Register(tc.def)
+		})
+	}
+}
+
+func TestRegisterMap(t *testing.T) {
+	m := oops.Map{errors.New("low level"): oops.New("high level", oops.Tag(registryTestLabelA)).(*oops.Error)}
+	before := len(oops.Maps())
+	oops.RegisterMap(m)
+	maps := oops.Maps()
+	if len(maps) != before+1 || len(maps[before]) != 1 {
+		t.Errorf("expected the map to be registered, got %v", maps)
+	}
+}
@@ -0,0 +1,135 @@
+package oops
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+)
+
+// RemoteLabel is the [Label] of an *[Error] decoded from another process whose code is not registered locally.
+// It is identified by its registry code rather than by pointer, so that [errors.Is] matches it against
+// the local [Label] registered under the same code, see [Register], and against other RemoteLabel values
+// with the same code.
+type RemoteLabel struct {
+	Code string
+	Text string
+}
+
+// Error implements golang's builtin error interface, returning the text of the remote [Label].
+func (l *RemoteLabel) Error() string { return l.Text }
+
+// Is reports whether target is registered, or is a RemoteLabel, under the code of the RemoteLabel.
+// If the code is not registered, the labels of [RegisterStdlib] are matched by their code.
+func (l *RemoteLabel) Is(target error) bool {
+	if l.Code == "" {
+		return false
+	}
+	if remote, ok := target.(*RemoteLabel); ok {
+		return remote.Code == l.Code
+	}
+	if def, ok := LookupCode(l.Code); ok {
+		return def.Label == target
+	}
+	if label, ok := stdlibLabel(l.Code); ok {
+		return label == target
+	}
+	return false
+}
+
+// labelCode returns the code the label is registered under, the code of a [RemoteLabel],
+// or the reserved code of an unregistered label of [RegisterStdlib].
+func labelCode(label Label) string {
+	if remote, ok := label.(*RemoteLabel); ok {
+		return remote.Code
+	}
+	if def, ok := Lookup(label); ok {
+		return def.Code
+	}
+	if code, ok := stdlibCode(label); ok {
+		return code
+	}
+	return ""
+}
+
+// UnmarshalJSON implements [json.Unmarshaler], decoding an *[Error] encoded by [Error.MarshalJSON],
+// typically in another process. The [Label] is the one registered locally under the encoded code,
+// or a [RemoteLabel] otherwise. Causes that were not an *[Error] are decoded as plain errors.
+// The decoded output is as redacted as the encoded one, and has no call site.
+func (err *Error) UnmarshalJSON(data []byte) error {
+	var v errorJSON
+	if jsonErr := json.Unmarshal(data, &v); jsonErr != nil {
+		return jsonErr
+	}
+	*err = *fromJSON(&v)
+	return nil
+}
+
+func fromJSON(v *errorJSON) *Error {
+	err := &Error{
+		id:          v.ID,
+		msg:         v.Message,
+		label:       remoteLabel(v.Label, v.Code),
+		correlation: Correlation{RequestID: v.RequestID, TraceID: v.TraceID, SpanID: v.SpanID},
+		occurrence:  Occurrence{Time: v.Time, Host: v.Host, PID: v.PID},
+		hops:        v.Hops,
+		frames:      v.Frames,
+	}
+	if v.Template != "" {
+		err.msg = v.Template
+		names := placeholders(v.Template)
+		for _, name := range names {
+			if value, ok := v.Params[name]; ok {
+				err.params = append(err.params, Param{Name: name, Value: value})
+			}
+		}
+		for _, name := range sortedKeys(v.Params) {
+			if _, ok := err.param(name); !ok {
+				err.params = append(err.params, Param{Name: name, Value: v.Params[name]})
+			}
+		}
+	}
+	for _, key := range sortedKeys(v.Attrs) {
+		err.attrs = append(err.attrs, Attr{Key: key, Value: v.Attrs[key]})
+	}
+	for _, cause := range v.Causes {
+		if cause == nil {
+			continue
+		}
+		if cause.Label == "" && cause.Code == "" && len(cause.Causes) == 0 {
+			err.stack = append(err.stack, errors.New(cause.Message))
+			continue
+		}
+		err.stack = append(err.stack, fromJSON(cause))
+	}
+	return err
+}
+
+// remoteLabel returns the local [Label] registered under code, or a [RemoteLabel].
+func remoteLabel(text, code string) Label {
+	if def, ok := LookupCode(code); ok {
+		return def.Label
+	}
+	// The labels of the Stdlib handlers are the same in every process, registered or not.
+	if label, ok := stdlibLabel(code); ok {
+		return label
+	}
+	if code == "" {
+		// The default labels are not registered, but are the same in every process.
+		switch text {
+		case "", Untagged.Error():
+			return Untagged
+		case Panicked.Error():
+			return Panicked
+		}
+	}
+	return &RemoteLabel{Code: code, Text: text}
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
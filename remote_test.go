@@ -0,0 +1,107 @@
+package oops_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/piteego/oops"
+	"github.com/piteego/oops/example"
+)
+
+func roundTrip(t *testing.T, err error) *oops.Error {
+	t.Helper()
+	data, jsonErr := json.Marshal(err)
+	if jsonErr != nil {
+		t.Fatalf("unexpected error: %v", jsonErr)
+	}
+	var decoded oops.Error
+	if jsonErr := json.Unmarshal(data, &decoded); jsonErr != nil {
+		t.Fatalf("unexpected error: %v", jsonErr)
+	}
+	return &decoded
+}
+
+func TestError_UnmarshalJSON(t *testing.T) {
+	inner := oops.Newf("user {id} not found", 42, oops.Tag(example.NotFound.Error), oops.Because(errors.New("record not found")))
+	err := oops.New("failed to process",
+		oops.Tag(example.Internal.Error),
+		oops.With("user", "alice"),
+		oops.Because(inner),
+	)
+	decoded := roundTrip(t, err)
+	if decoded.Label() != example.Internal.Error {
+		t.Errorf("expected the local label registered under the code, got %v", decoded.Label())
+	}
+	if !errors.Is(decoded, example.Internal.Error) || !errors.Is(decoded, example.NotFound.Error) {
+		t.Errorf("expected the decoded error to match the local labels")
+	}
+	if got, want := fmt.Sprintf("%+v", decoded), fmt.Sprintf("%+v", err); got != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, got)
+	}
+	causes := decoded.Causes()
+	var decodedInner *oops.Error
+	if len(causes) != 1 || !errors.As(causes[0], &decodedInner) {
+		t.Fatalf("expected an *oops.Error cause, got %v", causes)
+	}
+	if decodedInner.Template() != "user {id} not found" || decodedInner.Error() != "user 42 not found" {
+		t.Errorf("expected the template and params, got %q", decodedInner.Template())
+	}
+	if value, _ := decoded.Attr("user"); value != "alice" {
+		t.Errorf("expected the user attr, got %v", value)
+	}
+}
+
+func TestError_UnmarshalJSON_remoteLabel(t *testing.T) {
+	data := []byte(`{"message":"quota exceeded","label":"quota exceeded","code":"QUOTA_EXCEEDED","causes":[{"message":"boom","label":"untagged"}]}`)
+	var decoded oops.Error
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	remote, ok := decoded.Label().(*oops.RemoteLabel)
+	if !ok || remote.Code != "QUOTA_EXCEEDED" || remote.Error() != "quota exceeded" {
+		t.Fatalf("expected a remote label, got %#v", decoded.Label())
+	}
+	if !errors.Is(&decoded, &oops.RemoteLabel{Code: "QUOTA_EXCEEDED"}) {
+		t.Errorf("expected the remote label to match by code")
+	}
+	if errors.Is(&decoded, &oops.RemoteLabel{Code: "OTHER"}) || errors.Is(&decoded, example.Internal.Error) {
+		t.Errorf("expected the remote label not to match other labels")
+	}
+	if cause := decoded.Causes()[0].(*oops.Error); cause.Label() != oops.Untagged {
+		t.Errorf("expected the untagged label, got %v", cause.Label())
+	}
+}
+
+func TestRemoteLabel_Is(t *testing.T) {
+	testCases := []struct {
+		name   string
+		label  *oops.RemoteLabel
+		target error
+		want   bool
+	}{
+		{name: "registered code", label: &oops.RemoteLabel{Code: "30"}, target: example.NotFound.Error, want: true},
+		{name: "other registered label", label: &oops.RemoteLabel{Code: "30"}, target: example.Internal.Error, want: false},
+		{name: "unregistered stdlib code", label: &oops.RemoteLabel{Code: "TIMEOUT"}, target: oops.Timeout, want: true},
+		{name: "stdlib code registered by user", label: &oops.RemoteLabel{Code: "NOT_FOUND"}, target: oops.NotFound, want: false},
+		{name: "same code", label: &oops.RemoteLabel{Code: "X"}, target: &oops.RemoteLabel{Code: "X"}, want: true},
+		{name: "no code", label: &oops.RemoteLabel{Text: "x"}, target: &oops.RemoteLabel{Text: "x"}, want: false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := errors.Is(tc.label, tc.target); got != tc.want {
+				t.Errorf("expected %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestError_MarshalJSON_remoteLabel(t *testing.T) {
+	err := oops.New("quota exceeded", oops.Tag(&oops.RemoteLabel{Code: "QUOTA_EXCEEDED", Text: "quota exceeded"}))
+	data, _ := json.Marshal(err)
+	want := `{"message":"quota exceeded","label":"quota exceeded","code":"QUOTA_EXCEEDED"}`
+	if string(data) != want {
+		t.Errorf("expected %s, got %s", want, data)
+	}
+}
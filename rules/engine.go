@@ -0,0 +1,110 @@
+package rules
+
+import (
+	"context"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/piteego/oops"
+)
+
+// Engine handles errors with the rules of a file, which can be reloaded without a redeploy.
+// Reloads swap the whole [RuleSet] atomically: a concurrent call of the [Engine.Handler] uses either the old or the
+// new rules, never a mix of them, and an invalid file leaves the current rules in place. It is safe for concurrent use.
+type Engine struct {
+	path    string
+	current atomic.Pointer[RuleSet]
+
+	mu      sync.Mutex // serializes reloads
+	modTime time.Time
+	size    int64
+}
+
+// Load creates an [Engine] from the rules file at path. It fails with the [Diagnostics] of an invalid file.
+func Load(path string) (*Engine, error) {
+	e := &Engine{path: path}
+	if err := e.Reload(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Reload parses the rules file again and swaps the current rules with its rules.
+// If the file cannot be read or is invalid, it returns the error, e.g. [Diagnostics] located in the file,
+// and the current rules are kept.
+func (e *Engine) Reload() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	info, err := os.Stat(e.path)
+	if err != nil {
+		return err
+	}
+	return e.reload(info)
+}
+
+func (e *Engine) reload(info os.FileInfo) error {
+	data, err := os.ReadFile(e.path)
+	if err != nil {
+		return err
+	}
+	set, err := Parse(data)
+	if err != nil {
+		diags := err.(Diagnostics)
+		for i := range diags {
+			diags[i].File = e.path
+		}
+		return diags
+	}
+	e.current.Store(set)
+	e.modTime, e.size = info.ModTime(), info.Size()
+	return nil
+}
+
+// Rules returns the current rules.
+func (e *Engine) Rules() *RuleSet { return e.current.Load() }
+
+// Handle handles err with the current rules, see [RuleSet.Handle].
+func (e *Engine) Handle(err error) *oops.Error { return e.current.Load().Handle(err) }
+
+// Handler returns [Engine.Handle] as an [oops.Handler], always using the current rules.
+func (e *Engine) Handler() oops.Handler { return e.Handle }
+
+// Apply re-maps err with the current rules, including an already labeled *[oops.Error], see [RuleSet.Apply].
+func (e *Engine) Apply(err error) error { return e.current.Load().Apply(err) }
+
+// Watch polls the rules file every interval until ctx is done, reloading it when its modification time or size
+// changes. Reload errors are passed to onError, if not nil, and the current rules are kept.
+func (e *Engine) Watch(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if err := e.reloadIfChanged(); err != nil && onError != nil {
+			onError(err)
+		}
+	}
+}
+
+func (e *Engine) reloadIfChanged() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	info, err := os.Stat(e.path)
+	if err != nil {
+		return err
+	}
+	if info.ModTime().Equal(e.modTime) && info.Size() == e.size {
+		return nil
+	}
+	err = e.reload(info)
+	if err != nil {
+		// Do not report the same invalid file again until it changes.
+		e.modTime, e.size = info.ModTime(), info.Size()
+	}
+	return err
+}
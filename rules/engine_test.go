@@ -0,0 +1,162 @@
+package rules_test
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/piteego/oops"
+	"github.com/piteego/oops/example"
+	"github.com/piteego/oops/rules"
+)
+
+const (
+	notFoundRules = `{"rules": [{"name": "miss", "match": {"message": "missed"}, "label": "30", "message": "resource not found"}]}`
+	internalRules = `{"rules": [{"name": "miss", "match": {"message": "missed"}, "label": "1", "message": "something went wrong"}]}`
+)
+
+func writeRules(t *testing.T, path, doc string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(doc), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEngine_Reload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.json")
+	writeRules(t, path, notFoundRules)
+	engine, err := rules.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := engine.Handler()
+	if err := oops.Handle(example.RedisCacheMissed, handler); !errors.Is(err, example.NotFound.Error) {
+		t.Errorf("expected the loaded rules, got %v", err)
+	}
+
+	writeRules(t, path, internalRules)
+	if err := engine.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if err := oops.Handle(example.RedisCacheMissed, handler); !errors.Is(err, example.Internal.Error) {
+		t.Errorf("expected the reloaded rules, got %v", err)
+	}
+
+	writeRules(t, path, `{"rules": [{"name": "bad", "match": {"message": "("}, "label": "1", "message": "m"}]}`)
+	err = engine.Reload()
+	var diags rules.Diagnostics
+	if !errors.As(err, &diags) || !strings.HasPrefix(err.Error(), path+":1:12: rules[0].match.message: invalid regular expression") {
+		t.Errorf("expected diagnostics located in the file, got %v", err)
+	}
+	if err := oops.Handle(example.RedisCacheMissed, handler); !errors.Is(err, example.Internal.Error) {
+		t.Errorf("expected the previous rules to be kept, got %v", err)
+	}
+}
+
+func TestEngine_Apply(t *testing.T) {
+	engine, err := rules.Load("testdata/rules.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	timeout := oops.Handle(&url.Error{Op: "Get", URL: "http://example.com", Err: timeoutErr{}}, oops.Stdlib()...)
+	if got := engine.Apply(timeout); !errors.Is(got, oops.Unavailable) || !errors.Is(got, oops.Timeout) {
+		t.Errorf("expected the labeled error to be re-mapped, got %+v", got)
+	}
+	if got := engine.Apply(example.RedisCacheMissed); !errors.Is(got, example.NotFound.Error) {
+		t.Errorf("expected the cache miss rule, got %v", got)
+	}
+}
+
+func TestLoad_invalid(t *testing.T) {
+	if _, err := rules.Load(filepath.Join(t.TempDir(), "missing.json")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected a not exist error, got %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "rules.json")
+	writeRules(t, path, `{"rules": [}`)
+	if _, err := rules.Load(path); err == nil || !strings.HasPrefix(err.Error(), path+":1:12: ") {
+		t.Errorf("expected a located syntax error, got %v", err)
+	}
+}
+
+func TestEngine_Watch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.json")
+	writeRules(t, path, notFoundRules)
+	engine, err := rules.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 10)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		engine.Watch(ctx, time.Millisecond, func(err error) { errs <- err })
+	}()
+	defer func() { cancel(); <-done }()
+
+	waitFor := func(label oops.Label) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for !errors.Is(oops.Handle(example.RedisCacheMissed, engine.Handler()), label) {
+			if time.Now().After(deadline) {
+				t.Fatalf("expected the rules to be reloaded with label %v", label)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+	writeRules(t, path, internalRules+"\n") // a different size, whatever the resolution of modification times
+	waitFor(example.Internal.Error)
+
+	writeRules(t, path, `{"rules": []`)
+	select {
+	case err := <-errs:
+		if !strings.HasPrefix(err.Error(), path+":") {
+			t.Errorf("expected a located error, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the reload error to be reported")
+	}
+	if err := oops.Handle(example.RedisCacheMissed, engine.Handler()); !errors.Is(err, example.Internal.Error) {
+		t.Errorf("expected the previous rules to be kept, got %v", err)
+	}
+}
+
+func TestEngine_concurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.json")
+	writeRules(t, path, notFoundRules)
+	engine, err := rules.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				err := oops.Handle(example.RedisCacheMissed, engine.Handler())
+				if !errors.Is(err, example.NotFound.Error) && !errors.Is(err, example.Internal.Error) {
+					t.Errorf("unexpected result %v", err)
+					return
+				}
+			}
+		}()
+	}
+	for i := 0; i < 20; i++ {
+		doc := notFoundRules
+		if i%2 == 0 {
+			doc = internalRules
+		}
+		writeRules(t, path, doc)
+		if err := engine.Reload(); err != nil {
+			t.Error(err)
+		}
+	}
+	wg.Wait()
+}
@@ -0,0 +1,385 @@
+// Package rules maps errors to labels with declarative rules, loaded from a JSON document such as:
+//
+//	{
+//		"rules": [
+//			{
+//				"name": "cache miss",
+//				"match": {"type": "*errors.errorString", "message": "^redis: nil$"},
+//				"label": "NOT_FOUND",
+//				"message": "resource not found"
+//			},
+//			{
+//				"name": "upstream timeouts",
+//				"match": {"label": "TIMEOUT", "attrs": {"op": "Get"}},
+//				"label": "UNAVAILABLE",
+//				"message": "service temporarily unavailable"
+//			}
+//		]
+//	}
+//
+// A rule matches an error if all its conditions hold:
+//
+//   - type: the dynamic type of the error, or of one of its causes, as printed by %T;
+//   - message: a regular expression matching the message of the error;
+//   - label: the registry code of a [oops.Label] the error matches using [errors.Is];
+//   - attrs: the attribute values of the outermost *[oops.Error] in the error tree, as printed by %v.
+//
+// The first matching rule creates an *[oops.Error] with its message and the [oops.Label] registered under its code,
+// see [oops.Register]; the codes of the stdlib labels, such as "TIMEOUT", require [oops.RegisterStdlib].
+// Rules are exposed as an [oops.Handler] by [RuleSet.Handler] and by an [Engine],
+// which reloads them atomically from a file.
+//
+// As [oops.Handle] returns an *[oops.Error] as is, without invoking any handler, the label and attrs conditions
+// never match through the [oops.Handler]: use [Engine.Apply] or [RuleSet.Apply] to re-map errors that are
+// already labeled, e.g. at a layer boundary:
+//
+//	err = engine.Apply(oops.Handle(err, oops.Stdlib()...))
+package rules
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/piteego/oops"
+)
+
+// Diagnostic describes a problem of a rules document, at a 1-based line and column.
+type Diagnostic struct {
+	File         string // set by [Engine] only
+	Line, Column int
+	Field        string // e.g. "rules[1].match.message", empty for syntax errors
+	Message      string
+}
+
+// Error implements golang's builtin error interface, e.g. `rules.json:3:5: rules[0].label: unknown code "NOT_FUND"`.
+func (d Diagnostic) Error() string {
+	pos := fmt.Sprintf("%d:%d", d.Line, d.Column)
+	if d.File != "" {
+		pos = d.File + ":" + pos
+	}
+	if d.Field == "" {
+		return pos + ": " + d.Message
+	}
+	return pos + ": " + d.Field + ": " + d.Message
+}
+
+// Diagnostics is the error returned by [Parse] for an invalid rules document, listing all its problems.
+type Diagnostics []Diagnostic
+
+// Error implements golang's builtin error interface, one diagnostic per line.
+func (ds Diagnostics) Error() string {
+	lines := make([]string, len(ds))
+	for i := range ds {
+		lines[i] = ds[i].Error()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// RuleSet is an immutable list of parsed rules. It is safe for concurrent use.
+type RuleSet struct {
+	rules []rule
+}
+
+type rule struct {
+	name    string
+	typ     string
+	message *regexp.Regexp
+	label   oops.Label // to match
+	attrs   map[string]string
+	result  oops.Label
+	msg     string
+}
+
+// ruleJSON is the JSON representation of a rule.
+type ruleJSON struct {
+	Name  string `json:"name"`
+	Match struct {
+		Type    string            `json:"type"`
+		Message string            `json:"message"`
+		Label   string            `json:"label"`
+		Attrs   map[string]string `json:"attrs"`
+	} `json:"match"`
+	Label   string `json:"label"`
+	Message string `json:"message"`
+}
+
+// Parse parses a rules document. It rejects unknown fields, invalid regular expressions, unregistered codes
+// and rules without condition, label or message, returning [Diagnostics] locating every problem found.
+func Parse(data []byte) (*RuleSet, error) {
+	p := parser{data: data, dec: json.NewDecoder(bytes.NewReader(data))}
+	p.dec.DisallowUnknownFields()
+	set := p.parse()
+	if len(p.diags) > 0 {
+		return nil, p.diags
+	}
+	return set, nil
+}
+
+type parser struct {
+	data  []byte
+	dec   *json.Decoder
+	diags Diagnostics
+}
+
+// report adds a diagnostic at the given offset of the document.
+func (p *parser) report(offset int64, field, format string, args ...any) {
+	line, column := 1, 1
+	for _, c := range p.data[:min(int(offset), len(p.data))] {
+		if c == '\n' {
+			line, column = line+1, 1
+		} else {
+			column++
+		}
+	}
+	p.diags = append(p.diags, Diagnostic{Line: line, Column: column, Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// reportJSON adds a diagnostic for an error of the JSON decoder, located as precisely as possible.
+// base is the input offset of the decoder before the failed call.
+func (p *parser) reportJSON(err error, field string, base int64) {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		// The offset of a syntax error is the one of the input, just after the invalid character,
+		// or the end of the input if it is truncated.
+		offset := syntaxErr.Offset
+		if offset > 0 && !strings.HasPrefix(syntaxErr.Error(), "unexpected end") {
+			offset--
+		}
+		p.report(offset, "", "%s", syntaxErr.Error())
+	case errors.As(err, &typeErr):
+		// The offset of a type error is relative to the start of the decoded value, just after the invalid value.
+		if typeErr.Field != "" {
+			field += "." + typeErr.Field
+		}
+		p.report(p.valueStart(p.skip(base)+typeErr.Offset), field, "expected %s, got %s", jsonKind(typeErr.Type), typeErr.Value)
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		p.report(int64(len(p.data)), "", "unexpected end of input")
+	default:
+		p.report(p.skip(base), field, "%s", strings.TrimPrefix(err.Error(), "json: "))
+	}
+}
+
+// jsonKind returns the kind of JSON value decoded into t, as named in [json.UnmarshalTypeError.Value].
+func jsonKind(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Struct, reflect.Map:
+		return "object"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Bool:
+		return "bool"
+	case reflect.String:
+		return "string"
+	default:
+		return "number"
+	}
+}
+
+// valueStart returns the offset of the string, number or literal value ending at end,
+// or end itself for other values.
+func (p *parser) valueStart(end int64) int64 {
+	if end <= 0 || end > int64(len(p.data)) {
+		return end
+	}
+	start := end - 1
+	if p.data[start] == '"' {
+		for start--; start > 0 && (p.data[start] != '"' || p.data[start-1] == '\\'); start-- {
+		}
+		return start
+	}
+	for start > 0 && strings.IndexByte(" \t\r\n,:[{}]", p.data[start-1]) < 0 {
+		start--
+	}
+	if strings.IndexByte("}]", p.data[end-1]) >= 0 {
+		return end
+	}
+	return start
+}
+
+// start returns the offset of the next value of the decoder.
+func (p *parser) start() int64 { return p.skip(p.dec.InputOffset()) }
+
+// skip returns the offset of the first character from offset that is not a whitespace or a separator.
+func (p *parser) skip(offset int64) int64 {
+	for offset < int64(len(p.data)) && strings.IndexByte(" \t\r\n,:", p.data[offset]) >= 0 {
+		offset++
+	}
+	return offset
+}
+
+// delim reads the next token, reporting a diagnostic if it is not the given delimiter.
+func (p *parser) delim(want json.Delim, what string) bool {
+	base := p.dec.InputOffset()
+	offset := p.skip(base)
+	tok, err := p.dec.Token()
+	if err != nil {
+		p.reportJSON(err, "", base)
+		return false
+	}
+	if tok != want {
+		p.report(offset, "", "expected %s", what)
+		return false
+	}
+	return true
+}
+
+func (p *parser) parse() *RuleSet {
+	if !p.delim('{', "an object") {
+		return nil
+	}
+	set := &RuleSet{}
+	seen := false
+	for p.dec.More() {
+		base := p.dec.InputOffset()
+		offset := p.skip(base)
+		tok, err := p.dec.Token()
+		if err != nil {
+			p.reportJSON(err, "", base)
+			return nil
+		}
+		if key, _ := tok.(string); key != "rules" {
+			p.report(offset, "", "unknown field %q", tok)
+			return nil
+		}
+		if seen {
+			p.report(offset, "", "duplicate field \"rules\"")
+			return nil
+		}
+		seen = true
+		if !p.delim('[', "an array of rules") {
+			return nil
+		}
+		for i := 0; p.dec.More(); i++ {
+			base := p.dec.InputOffset()
+			offset := p.skip(base)
+			var v ruleJSON
+			if err := p.dec.Decode(&v); err != nil {
+				p.reportJSON(err, fmt.Sprintf("rules[%d]", i), base)
+				return nil
+			}
+			if r, ok := p.compile(v, fmt.Sprintf("rules[%d]", i), offset); ok {
+				set.rules = append(set.rules, r)
+			}
+		}
+		if !p.delim(']', "the end of the rules") {
+			return nil
+		}
+	}
+	if !p.delim('}', "the end of the document") {
+		return nil
+	}
+	if offset := p.start(); offset < int64(len(p.data)) {
+		p.report(offset, "", "unexpected data after the document")
+	}
+	if !seen {
+		p.report(0, "", "missing field \"rules\"")
+	}
+	return set
+}
+
+// compile validates a decoded rule, reporting its problems at the offset of the rule.
+func (p *parser) compile(v ruleJSON, field string, offset int64) (rule, bool) {
+	before := len(p.diags)
+	r := rule{name: v.Name, typ: v.Match.Type, attrs: v.Match.Attrs, msg: v.Message}
+	if v.Match.Message != "" {
+		var err error
+		if r.message, err = regexp.Compile(v.Match.Message); err != nil {
+			p.report(offset, field+".match.message", "invalid regular expression: %s", err)
+		}
+	}
+	if v.Match.Label != "" {
+		if def, ok := oops.LookupCode(v.Match.Label); ok {
+			r.label = def.Label
+		} else {
+			p.report(offset, field+".match.label", "unknown code %q", v.Match.Label)
+		}
+	}
+	if v.Match.Type == "" && v.Match.Message == "" && v.Match.Label == "" && len(v.Match.Attrs) == 0 {
+		p.report(offset, field+".match", "no condition, the rule would match every error")
+	}
+	if v.Label == "" {
+		p.report(offset, field+".label", "missing code")
+	} else if def, ok := oops.LookupCode(v.Label); ok {
+		r.result = def.Label
+	} else {
+		p.report(offset, field+".label", "unknown code %q", v.Label)
+	}
+	if v.Message == "" {
+		p.report(offset, field+".message", "missing message")
+	}
+	return r, len(p.diags) == before
+}
+
+// Len returns the number of rules of the set.
+func (s *RuleSet) Len() int { return len(s.rules) }
+
+// Handle returns a new *[oops.Error] for the first rule matching err, or nil if no rule matches.
+// The name of the rule is attached as the "rule" attribute.
+func (s *RuleSet) Handle(err error) *oops.Error {
+	for i := range s.rules {
+		if s.rules[i].matches(err) {
+			r := &s.rules[i]
+			return oops.New(r.msg, oops.Tag(r.result), oops.With("rule", r.name)).(*oops.Error)
+		}
+	}
+	return nil
+}
+
+// Handler returns [RuleSet.Handle] as an [oops.Handler].
+func (s *RuleSet) Handler() oops.Handler { return s.Handle }
+
+// Apply re-maps err with the first matching rule, including an *[oops.Error] that [oops.Handle] would return as is:
+// the result is the new *[oops.Error] of the rule, caused by err. It returns err if no rule matches, or if it is nil.
+func (s *RuleSet) Apply(err error) error {
+	if err == nil {
+		return nil
+	}
+	result := s.Handle(err)
+	if result == nil {
+		return err
+	}
+	oops.Because(err)(result)
+	return result
+}
+
+func (r *rule) matches(err error) bool {
+	if r.typ != "" && !hasType(err, r.typ) {
+		return false
+	}
+	if r.message != nil && !r.message.MatchString(err.Error()) {
+		return false
+	}
+	if r.label != nil && !errors.Is(err, r.label) {
+		return false
+	}
+	if len(r.attrs) > 0 {
+		var oopsErr *oops.Error
+		if !errors.As(err, &oopsErr) {
+			return false
+		}
+		for key, want := range r.attrs {
+			if value, ok := oopsErr.Attr(key); !ok || fmt.Sprint(value) != want {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// hasType reports whether err or one of its causes has the given dynamic type name.
+func hasType(err error, name string) bool {
+	found := false
+	oops.Walk(err, func(_ int, e error) bool {
+		found = fmt.Sprintf("%T", e) == name
+		return !found
+	})
+	return found
+}
@@ -0,0 +1,192 @@
+package rules_test
+
+import (
+	"errors"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/piteego/oops"
+	"github.com/piteego/oops/example"
+	"github.com/piteego/oops/rules"
+)
+
+func TestMain(m *testing.M) {
+	// The rules refer to the codes of the stdlib labels.
+	oops.RegisterStdlib()
+	os.Exit(m.Run())
+}
+
+func parseFile(t *testing.T, path string) *rules.RuleSet {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	set, err := rules.Parse(data)
+	if err != nil {
+		t.Fatalf("unexpected error:\n%v", err)
+	}
+	return set
+}
+
+func TestRuleSet_Handle(t *testing.T) {
+	set := parseFile(t, "testdata/rules.json")
+	if set.Len() != 2 {
+		t.Fatalf("expected 2 rules, got %d", set.Len())
+	}
+	t.Run("type and message", func(t *testing.T) {
+		err := oops.Handle(example.RedisCacheMissed, set.Handler())
+		if !errors.Is(err, example.NotFound.Error) || err.Error() != "resource not found" {
+			t.Errorf("expected the cache miss rule, got %v", err)
+		}
+		if rule, _ := err.(*oops.Error).Attr("rule"); rule != "cache miss" {
+			t.Errorf("expected the rule attr, got %v", rule)
+		}
+	})
+	t.Run("label and attrs", func(t *testing.T) {
+		timeout := oops.Handle(&url.Error{Op: "Get", URL: "http://example.com", Err: timeoutErr{}}, oops.Stdlib()...)
+		if got := oops.Handle(timeout, set.Handler()); got != timeout {
+			t.Errorf("expected oops.Handle to return the labeled error as is, got %v", got)
+		}
+		got := set.Apply(timeout)
+		if !errors.Is(got, oops.Unavailable) || got.Error() != "service temporarily unavailable" {
+			t.Errorf("expected the timeout rule, got %v", got)
+		}
+		if causes := got.(*oops.Error).Causes(); len(causes) != 1 || causes[0] != timeout {
+			t.Errorf("expected the labeled error as cause, got %v", causes)
+		}
+		chained := oops.Handle(&url.Error{Op: "Get", URL: "http://example.com", Err: timeoutErr{}},
+			oops.Chain(oops.First(oops.Stdlib()...), set.Handler()))
+		if !errors.Is(chained, oops.Unavailable) {
+			t.Errorf("expected the timeout rule to refine the stdlib result in a chain, got %v", chained)
+		}
+		post := oops.Handle(&url.Error{Op: "Post", URL: "http://example.com", Err: timeoutErr{}}, oops.Stdlib()...)
+		if got := set.Apply(post); got != post {
+			t.Errorf("expected no rule to match another op, got %v", got)
+		}
+		if set.Apply(nil) != nil {
+			t.Errorf("expected nil for nil")
+		}
+	})
+	t.Run("no match", func(t *testing.T) {
+		if got := set.Handle(errors.New("redis cache missed!")); got != nil {
+			t.Errorf("expected no rule to match, got %v", got)
+		}
+	})
+}
+
+type timeoutErr struct{}
+
+func (timeoutErr) Error() string   { return "i/o timeout" }
+func (timeoutErr) Timeout() bool   { return true }
+func (timeoutErr) Temporary() bool { return false }
+
+func TestParse_diagnostics(t *testing.T) {
+	testCases := []struct {
+		name string
+		doc  string
+		want []string
+	}{
+		{
+			name: "syntax error",
+			doc:  "{\n\t\"rules\": [\n\t\t{\"name\": \"x\",}\n\t]\n}",
+			want: []string{"3:16: invalid character '}' looking for beginning of object key string"},
+		},
+		{
+			name: "truncated",
+			doc:  `{"rules": [`,
+			want: []string{"1:12: unexpected end of JSON input"},
+		},
+		{
+			name: "not an object",
+			doc:  `[]`,
+			want: []string{"1:1: expected an object"},
+		},
+		{
+			name: "unknown top-level field",
+			doc:  `{"rule": []}`,
+			want: []string{`1:2: unknown field "rule"`},
+		},
+		{
+			name: "missing rules",
+			doc:  `{}`,
+			want: []string{`1:1: missing field "rules"`},
+		},
+		{
+			name: "unknown rule field",
+			doc:  "{\"rules\": [\n  {\"lable\": \"30\"}\n]}",
+			want: []string{`2:3: rules[0]: unknown field "lable"`},
+		},
+		{
+			name: "wrong type",
+			doc:  "{\"rules\": [\n  {\"match\": {\"attrs\": {\"id\": 42}}}\n]}",
+			want: []string{"2:30: rules[0].match.attrs.id: expected string, got number"},
+		},
+		{
+			name: "invalid rules",
+			doc: strings.Join([]string{
+				`{"rules": [`,
+				`  {"name": "ok", "match": {"label": "30"}, "label": "1", "message": "m"},`,
+				`  {"name": "bad", "match": {"message": "(", "label": "NOPE"}, "label": "NOT_FUND"},`,
+				`  {"name": "catch-all", "label": "1", "message": "m"}`,
+				`]}`,
+			}, "\n"),
+			want: []string{
+				"3:3: rules[1].match.message: invalid regular expression: error parsing regexp: missing closing ): `(`",
+				`3:3: rules[1].match.label: unknown code "NOPE"`,
+				`3:3: rules[1].label: unknown code "NOT_FUND"`,
+				"3:3: rules[1].message: missing message",
+				"4:3: rules[2].match: no condition, the rule would match every error",
+			},
+		},
+		{
+			name: "wrong string type",
+			doc:  "{\"rules\": [\n  {\"name\": \"x\", \"match\": \"a \\\"b\\\" c\"}\n]}",
+			want: []string{"2:26: rules[0].match: expected object, got string"},
+		},
+		{
+			name: "trailing data",
+			doc:  `{"rules": []} {}`,
+			want: []string{"1:15: unexpected data after the document"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			set, err := rules.Parse([]byte(tc.doc))
+			if set != nil {
+				t.Errorf("expected no rule set, got %d rules", set.Len())
+			}
+			var diags rules.Diagnostics
+			if !errors.As(err, &diags) {
+				t.Fatalf("expected diagnostics, got %v", err)
+			}
+			if got, want := err.Error(), strings.Join(tc.want, "\n"); got != want {
+				t.Errorf("expected:\n%s\ngot:\n%s", want, got)
+			}
+		})
+	}
+}
+
+func FuzzParse(f *testing.F) {
+	data, _ := os.ReadFile("testdata/rules.json")
+	f.Add(data)
+	f.Add([]byte(`{"rules": [{"match": {"attrs": {"id": 42}}}]}`))
+	f.Add([]byte(`{"rules": [{"match": "a \"b\" c"}]}`))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		set, err := rules.Parse(data)
+		if (set == nil) == (err == nil) {
+			t.Fatalf("expected either rules or an error, got %v and %v", set, err)
+		}
+		var diags rules.Diagnostics
+		if err != nil && (!errors.As(err, &diags) || len(diags) == 0) {
+			t.Fatalf("expected diagnostics, got %v", err)
+		}
+		for _, d := range diags {
+			if d.Line < 1 || d.Column < 1 {
+				t.Errorf("expected a position in the document, got %v", d)
+			}
+		}
+	})
+}
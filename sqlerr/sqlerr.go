@@ -0,0 +1,293 @@
+// Package sqlerr wraps [database/sql] so that every returned error is classified into an *[oops.Error].
+//
+// Driver errors are classified by their SQLSTATE code, read from the SQLState() string method many drivers implement:
+//
+//   - class 23 (integrity constraint violation): unique violations as [oops.AlreadyExists],
+//     the others (foreign key, not null, check) as [oops.InvalidInput];
+//   - class 40 (transaction rollback), e.g. serialization failures and deadlocks, as [oops.Aborted];
+//   - classes 08 (connection exception) and 53 (insufficient resources) as [oops.Unavailable],
+//     as well as [driver.ErrBadConn] and [sql.ErrConnDone].
+//
+// Other errors, e.g. [sql.ErrNoRows], are classified by the [oops.Stdlib] handlers, and context errors are tagged
+// as [oops.HandleContext] does. [oops.Retryable] reports whether the classified error may be retried.
+// Custom handlers given to [Wrap] take precedence, including over context errors, so repositories can keep
+// their own labels, e.g. for a query timeout:
+//
+//	db := sqlerr.Wrap(sqlDB, example.ErrMap.Handler())
+//	row := db.QueryRowContext(ctx, "SELECT name FROM users WHERE id = $1", id)
+//	if err := row.Scan(&name); errors.Is(err, oops.NotFound) { ... }
+package sqlerr
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
+
+	"github.com/piteego/oops"
+)
+
+// stater is implemented by the errors of drivers exposing the SQLSTATE code of the failure.
+type stater interface {
+	SQLState() string
+}
+
+// State returns the SQLSTATE code of the first error in the err tree implementing a SQLState() string method.
+func State(err error) (string, bool) {
+	var s stater
+	if !errors.As(err, &s) {
+		return "", false
+	}
+	return s.SQLState(), true
+}
+
+// Handler classifies the driver errors by their SQLSTATE code, and the connection errors of [database/sql].
+// It is used by the wrappers of this package after the custom handlers, and before the [oops.Stdlib] handlers.
+func Handler(err error) *oops.Error {
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) {
+		return oops.New("database connection failed", oops.Tag(oops.Unavailable)).(*oops.Error)
+	}
+	state, ok := State(err)
+	if !ok || len(state) != 5 {
+		return nil
+	}
+	switch {
+	case state == "23505":
+		return oops.New("record already exists", oops.Tag(oops.AlreadyExists), oops.With("sqlstate", state)).(*oops.Error)
+	case state == "23503":
+		return oops.New("referenced record does not exist", oops.Tag(oops.InvalidInput), oops.With("sqlstate", state)).(*oops.Error)
+	case state == "23502":
+		return oops.New("missing required value", oops.Tag(oops.InvalidInput), oops.With("sqlstate", state)).(*oops.Error)
+	case strings.HasPrefix(state, "23"):
+		return oops.New("integrity constraint violated", oops.Tag(oops.InvalidInput), oops.With("sqlstate", state)).(*oops.Error)
+	case state == "40001":
+		return oops.New("transaction serialization failure", oops.Tag(oops.Aborted), oops.With("sqlstate", state)).(*oops.Error)
+	case state == "40P01":
+		return oops.New("deadlock detected", oops.Tag(oops.Aborted), oops.With("sqlstate", state)).(*oops.Error)
+	case strings.HasPrefix(state, "40"):
+		return oops.New("transaction rolled back", oops.Tag(oops.Aborted), oops.With("sqlstate", state)).(*oops.Error)
+	case strings.HasPrefix(state, "08"), strings.HasPrefix(state, "53"):
+		return oops.New("database unavailable", oops.Tag(oops.Unavailable), oops.With("sqlstate", state)).(*oops.Error)
+	default:
+		return nil
+	}
+}
+
+// classifier runs the errors returned by [database/sql] through the custom handlers, [Handler] and [oops.Stdlib].
+type classifier struct {
+	handlers []oops.HandlerCtx
+}
+
+func newClassifier(custom []oops.Handler) classifier {
+	stdlib := oops.Stdlib()
+	handlers := make([]oops.HandlerCtx, 0, len(custom)+1+len(stdlib))
+	for _, h := range custom {
+		handlers = append(handlers, h.Ctx())
+	}
+	handlers = append(handlers, oops.Handler(Handler).Ctx())
+	for _, h := range stdlib {
+		handlers = append(handlers, h.Ctx())
+	}
+	return classifier{handlers: handlers}
+}
+
+// classify handles err with [oops.HandleContext], so the result is stamped with the correlation identifiers of ctx.
+func (c classifier) classify(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	return oops.HandleContext(ctx, err, c.handlers...)
+}
+
+// DB wraps a [sql.DB], classifying the errors it returns. Use [DB.Unwrap] for the methods not wrapped.
+type DB struct {
+	db *sql.DB
+	classifier
+}
+
+// Wrap returns a [DB] classifying the errors of db, using the given handlers before the built-in classification.
+func Wrap(db *sql.DB, handlers ...oops.Handler) *DB {
+	return &DB{db: db, classifier: newClassifier(handlers)}
+}
+
+// Open opens a database as [sql.Open] does, and wraps it.
+func Open(driverName, dataSourceName string, handlers ...oops.Handler) (*DB, error) {
+	c := newClassifier(handlers)
+	db, err := sql.Open(driverName, dataSourceName)
+	if err != nil {
+		return nil, c.classify(context.Background(), err)
+	}
+	return &DB{db: db, classifier: c}, nil
+}
+
+// Unwrap returns the wrapped [sql.DB].
+func (db *DB) Unwrap() *sql.DB { return db.db }
+
+// PingContext verifies the connection to the database, see [sql.DB.PingContext].
+func (db *DB) PingContext(ctx context.Context) error {
+	return db.classify(ctx, db.db.PingContext(ctx))
+}
+
+// Close closes the database, see [sql.DB.Close].
+func (db *DB) Close() error {
+	return db.classify(context.Background(), db.db.Close())
+}
+
+// ExecContext executes a query without returning any rows, see [sql.DB.ExecContext].
+func (db *DB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	result, err := db.db.ExecContext(ctx, query, args...)
+	return result, db.classify(ctx, err)
+}
+
+// Exec is like [DB.ExecContext] with the background context.
+func (db *DB) Exec(query string, args ...any) (sql.Result, error) {
+	return db.ExecContext(context.Background(), query, args...)
+}
+
+// QueryContext executes a query that returns rows, see [sql.DB.QueryContext].
+func (db *DB) QueryContext(ctx context.Context, query string, args ...any) (*Rows, error) {
+	rows, err := db.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, db.classify(ctx, err)
+	}
+	return &Rows{rows: rows, ctx: ctx, classifier: db.classifier}, nil
+}
+
+// Query is like [DB.QueryContext] with the background context.
+func (db *DB) Query(query string, args ...any) (*Rows, error) {
+	return db.QueryContext(context.Background(), query, args...)
+}
+
+// QueryRowContext executes a query that is expected to return at most one row, see [sql.DB.QueryRowContext].
+func (db *DB) QueryRowContext(ctx context.Context, query string, args ...any) *Row {
+	return &Row{row: db.db.QueryRowContext(ctx, query, args...), ctx: ctx, classifier: db.classifier}
+}
+
+// QueryRow is like [DB.QueryRowContext] with the background context.
+func (db *DB) QueryRow(query string, args ...any) *Row {
+	return db.QueryRowContext(context.Background(), query, args...)
+}
+
+// BeginTx starts a transaction, see [sql.DB.BeginTx].
+func (db *DB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
+	tx, err := db.db.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, db.classify(ctx, err)
+	}
+	return &Tx{tx: tx, ctx: ctx, classifier: db.classifier}, nil
+}
+
+// Begin is like [DB.BeginTx] with the background context and default options.
+func (db *DB) Begin() (*Tx, error) {
+	return db.BeginTx(context.Background(), nil)
+}
+
+// Tx wraps a [sql.Tx], classifying the errors it returns. Use [Tx.Unwrap] for the methods not wrapped.
+type Tx struct {
+	tx  *sql.Tx
+	ctx context.Context // of BeginTx, for Commit and Rollback
+	classifier
+}
+
+// Unwrap returns the wrapped [sql.Tx].
+func (tx *Tx) Unwrap() *sql.Tx { return tx.tx }
+
+// Commit commits the transaction, see [sql.Tx.Commit].
+func (tx *Tx) Commit() error {
+	return tx.classify(tx.ctx, tx.tx.Commit())
+}
+
+// Rollback aborts the transaction, see [sql.Tx.Rollback].
+func (tx *Tx) Rollback() error {
+	return tx.classify(tx.ctx, tx.tx.Rollback())
+}
+
+// ExecContext executes a query without returning any rows, see [sql.Tx.ExecContext].
+func (tx *Tx) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	result, err := tx.tx.ExecContext(ctx, query, args...)
+	return result, tx.classify(ctx, err)
+}
+
+// Exec is like [Tx.ExecContext] with the context of the transaction.
+func (tx *Tx) Exec(query string, args ...any) (sql.Result, error) {
+	return tx.ExecContext(tx.ctx, query, args...)
+}
+
+// QueryContext executes a query that returns rows, see [sql.Tx.QueryContext].
+func (tx *Tx) QueryContext(ctx context.Context, query string, args ...any) (*Rows, error) {
+	rows, err := tx.tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, tx.classify(ctx, err)
+	}
+	return &Rows{rows: rows, ctx: ctx, classifier: tx.classifier}, nil
+}
+
+// Query is like [Tx.QueryContext] with the context of the transaction.
+func (tx *Tx) Query(query string, args ...any) (*Rows, error) {
+	return tx.QueryContext(tx.ctx, query, args...)
+}
+
+// QueryRowContext executes a query that is expected to return at most one row, see [sql.Tx.QueryRowContext].
+func (tx *Tx) QueryRowContext(ctx context.Context, query string, args ...any) *Row {
+	return &Row{row: tx.tx.QueryRowContext(ctx, query, args...), ctx: ctx, classifier: tx.classifier}
+}
+
+// QueryRow is like [Tx.QueryRowContext] with the context of the transaction.
+func (tx *Tx) QueryRow(query string, args ...any) *Row {
+	return tx.QueryRowContext(tx.ctx, query, args...)
+}
+
+// Rows wraps a [sql.Rows], classifying the errors it returns. Use [Rows.Unwrap] for the methods not wrapped.
+type Rows struct {
+	rows *sql.Rows
+	ctx  context.Context // of the query
+	classifier
+}
+
+// Unwrap returns the wrapped [sql.Rows].
+func (rows *Rows) Unwrap() *sql.Rows { return rows.rows }
+
+// Next prepares the next row for reading with [Rows.Scan], see [sql.Rows.Next].
+func (rows *Rows) Next() bool { return rows.rows.Next() }
+
+// NextResultSet prepares the next result set for reading, see [sql.Rows.NextResultSet].
+func (rows *Rows) NextResultSet() bool { return rows.rows.NextResultSet() }
+
+// Columns returns the column names, see [sql.Rows.Columns].
+func (rows *Rows) Columns() ([]string, error) {
+	columns, err := rows.rows.Columns()
+	return columns, rows.classify(rows.ctx, err)
+}
+
+// Scan copies the columns of the current row into dest, see [sql.Rows.Scan].
+func (rows *Rows) Scan(dest ...any) error {
+	return rows.classify(rows.ctx, rows.rows.Scan(dest...))
+}
+
+// Err returns the error encountered during iteration, if any, see [sql.Rows.Err].
+func (rows *Rows) Err() error {
+	return rows.classify(rows.ctx, rows.rows.Err())
+}
+
+// Close closes the rows, see [sql.Rows.Close].
+func (rows *Rows) Close() error {
+	return rows.classify(rows.ctx, rows.rows.Close())
+}
+
+// Row wraps a [sql.Row], classifying the errors it returns, e.g. [sql.ErrNoRows] as [oops.NotFound].
+type Row struct {
+	row *sql.Row
+	ctx context.Context // of the query
+	classifier
+}
+
+// Scan copies the columns of the row into dest, see [sql.Row.Scan].
+func (row *Row) Scan(dest ...any) error {
+	return row.classify(row.ctx, row.row.Scan(dest...))
+}
+
+// Err returns the error of the query, if any, see [sql.Row.Err].
+func (row *Row) Err() error {
+	return row.classify(row.ctx, row.row.Err())
+}
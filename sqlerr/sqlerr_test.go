@@ -0,0 +1,292 @@
+package sqlerr_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/piteego/oops"
+	"github.com/piteego/oops/example"
+	"github.com/piteego/oops/oopstest"
+	"github.com/piteego/oops/sqlerr"
+)
+
+// stateErr is a driver error exposing its SQLSTATE code, as the errors of most drivers do.
+type stateErr string
+
+func (e stateErr) Error() string    { return "driver error " + string(e) }
+func (e stateErr) SQLState() string { return string(e) }
+
+// fakeDriver scripts its results by query:
+//
+//	FAIL <sqlstate>        fails with the SQLSTATE error
+//	BADCONN                fails with driver.ErrBadConn
+//	SELECT <v1> <v2>...    returns a "name" column with a row per value
+//	ITERATE <sqlstate>     returns a row, then fails with the SQLSTATE error
+//	COMMIT <sqlstate>      makes the commit of the transaction fail with the SQLSTATE error
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) { return &fakeConn{}, nil }
+
+type fakeConn struct {
+	commitErr error
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{conn: c, query: query}, nil
+}
+func (c *fakeConn) Close() error              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) { return &fakeTx{conn: c}, nil }
+
+type fakeTx struct{ conn *fakeConn }
+
+func (tx *fakeTx) Commit() error {
+	err := tx.conn.commitErr
+	tx.conn.commitErr = nil
+	return err
+}
+func (tx *fakeTx) Rollback() error { return nil }
+
+type fakeStmt struct {
+	conn  *fakeConn
+	query string
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec([]driver.Value) (driver.Result, error) {
+	verb, arg, _ := strings.Cut(s.query, " ")
+	switch verb {
+	case "FAIL":
+		return nil, stateErr(arg)
+	case "BADCONN":
+		return nil, driver.ErrBadConn
+	case "COMMIT":
+		s.conn.commitErr = stateErr(arg)
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	verb, arg, _ := strings.Cut(s.query, " ")
+	switch verb {
+	case "FAIL":
+		return nil, stateErr(arg)
+	case "BADCONN":
+		return nil, driver.ErrBadConn
+	case "ITERATE":
+		return &fakeRows{values: []string{"alice"}, err: stateErr(arg)}, nil
+	}
+	return &fakeRows{values: strings.Fields(arg)}, nil
+}
+
+type fakeRows struct {
+	values []string
+	err    error
+}
+
+func (r *fakeRows) Columns() []string { return []string{"name"} }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		if r.err != nil {
+			return r.err
+		}
+		return io.EOF
+	}
+	dest[0], r.values = r.values[0], r.values[1:]
+	return nil
+}
+
+func init() {
+	sql.Register("sqlerr-fake", fakeDriver{})
+}
+
+func open(t *testing.T, handlers ...oops.Handler) *sqlerr.DB {
+	t.Helper()
+	db, err := sqlerr.Open("sqlerr-fake", "", handlers...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestDB_ExecContext(t *testing.T) {
+	db := open(t)
+	testCases := []struct {
+		query string
+		label oops.Label
+		msg   string
+	}{
+		{query: "FAIL 23505", label: oops.AlreadyExists, msg: "record already exists"},
+		{query: "FAIL 23503", label: oops.InvalidInput, msg: "referenced record does not exist"},
+		{query: "FAIL 23502", label: oops.InvalidInput, msg: "missing required value"},
+		{query: "FAIL 23514", label: oops.InvalidInput, msg: "integrity constraint violated"},
+		{query: "FAIL 40001", label: oops.Aborted, msg: "transaction serialization failure"},
+		{query: "FAIL 40P01", label: oops.Aborted, msg: "deadlock detected"},
+		{query: "FAIL 08006", label: oops.Unavailable, msg: "database unavailable"},
+		{query: "FAIL 53300", label: oops.Unavailable, msg: "database unavailable"},
+		{query: "BADCONN", label: oops.Unavailable, msg: "database connection failed"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.query, func(t *testing.T) {
+			_, err := db.ExecContext(context.Background(), tc.query)
+			oopstest.AssertLabel(t, err, tc.label)
+			oopstest.AssertMessage(t, err, tc.msg)
+			if state, ok := sqlerr.State(err); tc.query != "BADCONN" && (!ok || state != tc.query[5:]) {
+				t.Errorf("expected the sqlstate of the driver error, got %q", state)
+			}
+		})
+	}
+}
+
+func TestDB_ExecContext_unclassified(t *testing.T) {
+	_, err := open(t).Exec("FAIL 42601")
+	if _, ok := err.(*oops.Error); ok {
+		t.Errorf("expected the driver error, got %v", err)
+	}
+	if state, _ := sqlerr.State(err); state != "42601" {
+		t.Errorf("expected the driver error, got %v", err)
+	}
+}
+
+func TestDB_QueryRow(t *testing.T) {
+	db := open(t)
+	var name string
+	if err := db.QueryRow("SELECT alice").Scan(&name); err != nil || name != "alice" {
+		t.Fatalf("expected alice, got %q, %v", name, err)
+	}
+	err := db.QueryRowContext(context.Background(), "SELECT").Scan(&name)
+	oopstest.AssertLabel(t, err, oops.NotFound)
+	oopstest.AssertMessage(t, err, "record not found")
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected sql.ErrNoRows as a cause")
+	}
+	err = db.QueryRow("FAIL 40001").Err()
+	oopstest.AssertLabel(t, err, oops.Aborted)
+	oopstest.AssertMessage(t, err, "transaction serialization failure")
+}
+
+func TestDB_Query(t *testing.T) {
+	db := open(t)
+	rows, err := db.Query("SELECT alice bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, name)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if err := rows.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(names, ",") != "alice,bob" {
+		t.Errorf("expected alice,bob, got %v", names)
+	}
+
+	rows, err = db.Query("ITERATE 40P01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for rows.Next() {
+	}
+	err = rows.Err()
+	oopstest.AssertLabel(t, err, oops.Aborted)
+	oopstest.AssertMessage(t, err, "deadlock detected")
+
+	_, err = db.Query("FAIL 23505")
+	oopstest.AssertLabel(t, err, oops.AlreadyExists)
+	oopstest.AssertMessage(t, err, "record already exists")
+}
+
+func TestTx(t *testing.T) {
+	db := open(t)
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = tx.Exec("FAIL 23505")
+	oopstest.AssertLabel(t, err, oops.AlreadyExists)
+	oopstest.AssertMessage(t, err, "record already exists")
+	if _, err := tx.Exec("COMMIT 40001"); err != nil {
+		t.Fatal(err)
+	}
+	err = tx.Commit()
+	oopstest.AssertLabel(t, err, oops.Aborted)
+	oopstest.AssertMessage(t, err, "transaction serialization failure")
+	if !oops.Retryable(err) {
+		t.Errorf("expected a serialization failure to be retryable")
+	}
+	err = tx.Rollback()
+	oopstest.AssertLabel(t, err, oops.FailedPrecondition)
+	oopstest.AssertMessage(t, err, "transaction already committed or rolled back")
+}
+
+func TestWrap_customHandlers(t *testing.T) {
+	duplicated := func(err error) *oops.Error {
+		if state, _ := sqlerr.State(err); state == "23505" {
+			return oops.New("duplicated entity", oops.Tag(example.Duplication.Error)).(*oops.Error)
+		}
+		return nil
+	}
+	db := open(t, duplicated)
+	_, err := db.Exec("FAIL 23505")
+	oopstest.AssertLabel(t, err, example.Duplication.Error)
+	oopstest.AssertMessage(t, err, "duplicated entity")
+	_, err = db.Exec("FAIL 23503")
+	oopstest.AssertLabel(t, err, oops.InvalidInput)
+	oopstest.AssertMessage(t, err, "referenced record does not exist")
+}
+
+func TestWrap_customHandlers_context(t *testing.T) {
+	timeout := func(err error) *oops.Error {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return oops.New("query timed out", oops.Tag(example.Unprocessable.Error)).(*oops.Error)
+		}
+		return nil
+	}
+	var hooks oops.Hooks
+	var handled []oops.Label
+	hooks.OnHandle(func(_ error, result *oops.Error) { handled = append(handled, result.Label()) })
+	ctx := oops.ContextWithHooks(oops.ContextWithRequestID(context.Background(), "req-8"), &hooks)
+	ctx, cancel := context.WithDeadline(ctx, time.Unix(0, 0))
+	defer cancel()
+	_, err := open(t).ExecContext(ctx, "SELECT 1")
+	oopstest.AssertLabel(t, err, oops.DeadlineExceeded)
+	oopstest.AssertMessage(t, err, "operation deadline exceeded")
+	_, err = open(t, timeout).ExecContext(ctx, "SELECT 1")
+	oopstest.AssertLabel(t, err, example.Unprocessable.Error)
+	oopstest.AssertMessage(t, err, "query timed out")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the context error as cause")
+	}
+	if got := err.(*oops.Error).RequestID(); got != "req-8" {
+		t.Errorf("expected request id req-8, got %q", got)
+	}
+	if len(handled) != 2 || handled[1] != example.Unprocessable.Error {
+		t.Errorf("expected the scoped hooks to see both errors, got %v", handled)
+	}
+}
+
+func TestDB_correlation(t *testing.T) {
+	ctx := oops.ContextWithRequestID(context.Background(), "req-7")
+	_, err := open(t).ExecContext(ctx, "FAIL 23505")
+	if got := err.(*oops.Error).RequestID(); got != "req-7" {
+		t.Errorf("expected request id req-7, got %q", got)
+	}
+}
@@ -0,0 +1,204 @@
+package oops
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"net"
+	"net/url"
+	"reflect"
+	"strconv"
+	"sync"
+)
+
+// Labels of the standard library errors classified by the [Stdlib] handlers.
+// Along with [Canceled] and [DeadlineExceeded], they are not registered unless [RegisterStdlib] is called.
+var (
+	// NotFound label is tagged to errors caused by [fs.ErrNotExist] and [sql.ErrNoRows].
+	NotFound Label = errors.New("not found")
+	// AlreadyExists label is tagged to errors caused by [fs.ErrExist].
+	AlreadyExists Label = errors.New("already exists")
+	// PermissionDenied label is tagged to errors caused by [fs.ErrPermission].
+	PermissionDenied Label = errors.New("permission denied")
+	// InvalidInput label is tagged to errors caused by malformed input: [io.EOF], [io.ErrUnexpectedEOF],
+	// JSON decoding errors and [strconv] errors.
+	InvalidInput Label = errors.New("invalid input")
+	// FailedPrecondition label is tagged to errors caused by [sql.ErrTxDone].
+	FailedPrecondition Label = errors.New("failed precondition")
+	// Timeout label is tagged to errors caused by network timeouts.
+	Timeout Label = errors.New("timeout")
+	// Unavailable label is tagged to errors caused by other network failures.
+	Unavailable Label = errors.New("unavailable")
+	// Aborted label is tagged to errors of operations aborted by a concurrency conflict,
+	// such as a transaction serialization failure or a deadlock. See [Retryable].
+	Aborted Label = errors.New("aborted")
+)
+
+// stdlibDefinitions are the definitions registered by [RegisterStdlib].
+var stdlibDefinitions = []Definition{
+	{Label: Canceled, Code: "CANCELED", Description: "The operation was canceled by the caller.", HTTPStatus: 499, GRPCCode: 1},
+	{Label: DeadlineExceeded, Code: "DEADLINE_EXCEEDED", Description: "The operation did not complete before its deadline.", HTTPStatus: 504, GRPCCode: 4},
+	{Label: NotFound, Code: "NOT_FOUND", Description: "The requested resource was not found.", HTTPStatus: 404, GRPCCode: 5},
+	{Label: AlreadyExists, Code: "ALREADY_EXISTS", Description: "The resource already exists.", HTTPStatus: 409, GRPCCode: 6},
+	{Label: PermissionDenied, Code: "PERMISSION_DENIED", Description: "The caller is not allowed to access the resource.", HTTPStatus: 403, GRPCCode: 7},
+	{Label: InvalidInput, Code: "INVALID_INPUT", Description: "The input is malformed or out of range.", HTTPStatus: 400, GRPCCode: 3},
+	{Label: FailedPrecondition, Code: "FAILED_PRECONDITION", Description: "The system is not in a state required by the operation.", HTTPStatus: 412, GRPCCode: 9},
+	{Label: Timeout, Code: "TIMEOUT", Description: "A dependency did not respond in time.", HTTPStatus: 504, GRPCCode: 4},
+	{Label: Unavailable, Code: "UNAVAILABLE", Description: "A dependency is currently unavailable.", HTTPStatus: 503, GRPCCode: 14},
+	{Label: Aborted, Code: "ABORTED", Description: "The operation was aborted by a concurrency conflict.", HTTPStatus: 409, GRPCCode: 10},
+}
+
+var registerStdlib sync.Once
+
+// RegisterStdlib registers the labels of the [Stdlib] handlers, [Canceled] and [DeadlineExceeded]
+// with codes such as "NOT_FOUND", "TIMEOUT" or "CANCELED", so that they are listed in the catalog and
+// can be referred to by code, e.g. by the rules package. It is opt-in, as an application may register these
+// common codes for its own labels. Calling it again does nothing, and it panics like [Register] if a code is taken.
+//
+// Unregistered, these labels are encoded by [Error.MarshalJSON] and [Error.MarshalBinary] with their code
+// prefixed by "oops.", e.g. "oops.NOT_FOUND", which is reserved, and decoded as themselves in every process.
+func RegisterStdlib() {
+	registerStdlib.Do(func() { Register(stdlibDefinitions...) })
+}
+
+// stdlibCodePrefix prefixes the codes of the unregistered labels of [RegisterStdlib], reserved by [Register].
+const stdlibCodePrefix = "oops."
+
+// stdlibLabel returns the label of [RegisterStdlib] with the given code, prefixed by stdlibCodePrefix or not.
+func stdlibLabel(code string) (Label, bool) {
+	for _, def := range stdlibDefinitions {
+		if code == def.Code || code == stdlibCodePrefix+def.Code {
+			return def.Label, true
+		}
+	}
+	return nil, false
+}
+
+// stdlibCode returns the reserved code of an unregistered label of [RegisterStdlib].
+func stdlibCode(label Label) (string, bool) {
+	for _, def := range stdlibDefinitions {
+		if def.Label == label {
+			return stdlibCodePrefix + def.Code, true
+		}
+	}
+	return "", false
+}
+
+// Retryable reports whether err is labeled as [Aborted], [Unavailable] or [Timeout],
+// i.e. whether the failed operation may succeed if it is retried as is.
+func Retryable(err error) bool {
+	return errors.Is(err, Aborted) || errors.Is(err, Unavailable) || errors.Is(err, Timeout)
+}
+
+// Stdlib returns the handlers classifying the errors of the standard library into predefined labels:
+//
+//   - [context.Canceled] and [context.DeadlineExceeded] as [Canceled] and [DeadlineExceeded];
+//   - [*url.Error] and [net.Error] as [Timeout] if they timed out, [Unavailable] otherwise, whatever their cause,
+//     e.g. a connection dropped with [io.EOF];
+//   - [fs.ErrNotExist], [fs.ErrExist] and [fs.ErrPermission] as [NotFound], [AlreadyExists] and [PermissionDenied];
+//   - [io.EOF] and [io.ErrUnexpectedEOF] as [InvalidInput];
+//   - [sql.ErrNoRows] and [sql.ErrTxDone] as [NotFound] and [FailedPrecondition];
+//   - [*json.SyntaxError], [*json.UnmarshalTypeError] and [*strconv.NumError] as [InvalidInput].
+//
+// Start with them and add your own handlers in front, to override the classification:
+//
+//	oops.Handle(err, append([]oops.Handler{example.ErrMap.Handler()}, oops.Stdlib()...)...)
+func Stdlib() []Handler {
+	return []Handler{
+		handleContextErr,
+		handleURLErr,
+		handleNetErr,
+		handleFSErr,
+		handleIOErr,
+		handleSQLErr,
+		handleJSONErr,
+		handleStrconvErr,
+	}
+}
+
+func handleFSErr(err error) *Error {
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return New("file does not exist", Tag(NotFound)).(*Error)
+	case errors.Is(err, fs.ErrExist):
+		return New("file already exists", Tag(AlreadyExists)).(*Error)
+	case errors.Is(err, fs.ErrPermission):
+		return New("permission denied", Tag(PermissionDenied)).(*Error)
+	default:
+		return nil
+	}
+}
+
+func handleIOErr(err error) *Error {
+	switch {
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return New("unexpected end of input", Tag(InvalidInput)).(*Error)
+	case errors.Is(err, io.EOF):
+		return New("end of input", Tag(InvalidInput)).(*Error)
+	default:
+		return nil
+	}
+}
+
+func handleSQLErr(err error) *Error {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return New("record not found", Tag(NotFound)).(*Error)
+	case errors.Is(err, sql.ErrTxDone):
+		return New("transaction already committed or rolled back", Tag(FailedPrecondition)).(*Error)
+	default:
+		return nil
+	}
+}
+
+// handleURLErr classifies the errors of HTTP clients, before handleNetErr as an [*url.Error] is also a [net.Error],
+// and before handleIOErr as a dropped connection is reported as an [*url.Error] caused by [io.EOF].
+func handleURLErr(err error) *Error {
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) {
+		return nil
+	}
+	if urlErr.Timeout() {
+		return New("request timed out", Tag(Timeout), With("op", urlErr.Op)).(*Error)
+	}
+	return New("request failed", Tag(Unavailable), With("op", urlErr.Op)).(*Error)
+}
+
+// handleNetErr classifies network errors, before handleIOErr as a connection may be dropped with [io.EOF].
+// Syscall errors are [net.Error] values too, but they are network errors only if wrapped by one,
+// e.g. by a [*net.OpError]: otherwise, such as the ones of the file system, they are left to the next handlers.
+func handleNetErr(err error) *Error {
+	var netErr net.Error
+	if !errors.As(err, &netErr) || reflect.TypeOf(netErr).PkgPath() == "syscall" {
+		return nil
+	}
+	if netErr.Timeout() {
+		return New("network timeout", Tag(Timeout)).(*Error)
+	}
+	return New("network unavailable", Tag(Unavailable)).(*Error)
+}
+
+func handleJSONErr(err error) *Error {
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return New("malformed JSON", Tag(InvalidInput), With("offset", syntaxErr.Offset)).(*Error)
+	}
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return New("invalid JSON value", Tag(InvalidInput), With("field", typeErr.Field), With("offset", typeErr.Offset)).(*Error)
+	}
+	return nil
+}
+
+func handleStrconvErr(err error) *Error {
+	var numErr *strconv.NumError
+	if !errors.As(err, &numErr) {
+		return nil
+	}
+	if errors.Is(numErr.Err, strconv.ErrRange) {
+		return New("number out of range", Tag(InvalidInput)).(*Error)
+	}
+	return New("invalid number", Tag(InvalidInput)).(*Error)
+}
@@ -0,0 +1,178 @@
+package oops_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"syscall"
+	"testing"
+
+	"github.com/piteego/oops"
+	"github.com/piteego/oops/example"
+)
+
+type timeoutErr struct{ timeout bool }
+
+func (e timeoutErr) Error() string   { return "i/o failure" }
+func (e timeoutErr) Timeout() bool   { return e.timeout }
+func (e timeoutErr) Temporary() bool { return false }
+
+func TestStdlib(t *testing.T) {
+	syntaxErr := json.Unmarshal([]byte("{"), &struct{}{})
+	var typed struct{ Age int }
+	typeErr := json.Unmarshal([]byte(`{"Age":"old"}`), &typed)
+	_, numErr := strconv.Atoi("x")
+	_, rangeErr := strconv.ParseInt("99999999999999999999", 10, 64)
+	_, openErr := os.Open("/does/not/exist")
+	testCases := []struct {
+		name  string
+		err   error
+		label oops.Label
+		msg   string
+	}{
+		{name: "context canceled", err: context.Canceled, label: oops.Canceled, msg: "operation canceled"},
+		{name: "context deadline", err: fmt.Errorf("query: %w", context.DeadlineExceeded), label: oops.DeadlineExceeded, msg: "operation deadline exceeded"},
+		{name: "fs not exist", err: openErr, label: oops.NotFound, msg: "file does not exist"},
+		{name: "fs exist", err: fs.ErrExist, label: oops.AlreadyExists, msg: "file already exists"},
+		{name: "fs permission", err: &fs.PathError{Op: "open", Path: "/root", Err: fs.ErrPermission}, label: oops.PermissionDenied, msg: "permission denied"},
+		{name: "fs link exist", err: &os.LinkError{Op: "link", Old: "a", New: "b", Err: syscall.EEXIST}, label: oops.AlreadyExists, msg: "file already exists"},
+		{name: "io EOF", err: io.EOF, label: oops.InvalidInput, msg: "end of input"},
+		{name: "io unexpected EOF", err: io.ErrUnexpectedEOF, label: oops.InvalidInput, msg: "unexpected end of input"},
+		{name: "sql no rows", err: fmt.Errorf("get user: %w", sql.ErrNoRows), label: oops.NotFound, msg: "record not found"},
+		{name: "sql tx done", err: sql.ErrTxDone, label: oops.FailedPrecondition, msg: "transaction already committed or rolled back"},
+		{name: "url timeout", err: &url.Error{Op: "Get", URL: "http://example.com", Err: timeoutErr{timeout: true}}, label: oops.Timeout, msg: "request timed out"},
+		{name: "url failure", err: &url.Error{Op: "Get", URL: "http://example.com", Err: errors.New("connection refused")}, label: oops.Unavailable, msg: "request failed"},
+		{name: "url dropped connection", err: &url.Error{Op: "Post", URL: "http://example.com", Err: io.EOF}, label: oops.Unavailable, msg: "request failed"},
+		{name: "url unexpected EOF", err: &url.Error{Op: "Get", URL: "http://example.com", Err: io.ErrUnexpectedEOF}, label: oops.Unavailable, msg: "request failed"},
+		{name: "url canceled", err: &url.Error{Op: "Get", URL: "http://example.com", Err: context.Canceled}, label: oops.Canceled, msg: "operation canceled"},
+		{name: "net timeout", err: &net.OpError{Op: "dial", Net: "tcp", Err: timeoutErr{timeout: true}}, label: oops.Timeout, msg: "network timeout"},
+		{name: "net dropped connection", err: &net.OpError{Op: "read", Net: "tcp", Err: io.EOF}, label: oops.Unavailable, msg: "network unavailable"},
+		{name: "net failure", err: &net.OpError{Op: "dial", Net: "tcp", Err: timeoutErr{}}, label: oops.Unavailable, msg: "network unavailable"},
+		{name: "json syntax", err: syntaxErr, label: oops.InvalidInput, msg: "malformed JSON"},
+		{name: "json type", err: typeErr, label: oops.InvalidInput, msg: "invalid JSON value"},
+		{name: "strconv syntax", err: numErr, label: oops.InvalidInput, msg: "invalid number"},
+		{name: "strconv range", err: rangeErr, label: oops.InvalidInput, msg: "number out of range"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := oops.Handle(tc.err, oops.Stdlib()...)
+			var oopsErr *oops.Error
+			if !errors.As(got, &oopsErr) {
+				t.Fatalf("expected an *oops.Error, got %T: %v", got, got)
+			}
+			if oopsErr.Label() != tc.label {
+				t.Errorf("expected label %q, got %q", tc.label, oopsErr.Label())
+			}
+			if got.Error() != tc.msg {
+				t.Errorf("expected message %q, got %q", tc.msg, got.Error())
+			}
+			if causes := oopsErr.Causes(); len(causes) != 1 || causes[0] != tc.err {
+				t.Errorf("expected the original error as the only cause, got %v", causes)
+			}
+		})
+	}
+}
+
+func TestStdlib_attrs(t *testing.T) {
+	var typed struct{ Age int }
+	err := oops.Handle(json.Unmarshal([]byte(`{"Age":"old"}`), &typed), oops.Stdlib()...).(*oops.Error)
+	if field, _ := err.Attr("field"); field != "Age" {
+		t.Errorf("expected the field attr, got %v", field)
+	}
+}
+
+func TestStdlib_unclassified(t *testing.T) {
+	err := errors.New("unknown")
+	if got := oops.Handle(err, oops.Stdlib()...); got != err {
+		t.Errorf("expected the original error, got %v", got)
+	}
+}
+
+func TestStdlib_overridden(t *testing.T) {
+	errMap := oops.Map{fs.ErrNotExist: oops.New("avatar not found", oops.Tag(example.NotFound.Error)).(*oops.Error)}
+	handlers := append([]oops.Handler{errMap.Handler()}, oops.Stdlib()...)
+	got := oops.Handle(fs.ErrNotExist, handlers...)
+	if !errors.Is(got, example.NotFound.Error) || errors.Is(got, oops.NotFound) {
+		t.Errorf("expected the map to take precedence, got %+v", got)
+	}
+}
+
+// userNotFound is registered under a code also used by [oops.RegisterStdlib], which is not called by these tests.
+var userNotFound = errors.New("user not found")
+
+func init() {
+	oops.Register(oops.Definition{Label: userNotFound, Code: "NOT_FOUND", HTTPStatus: 404})
+}
+
+func TestStdlib_userCode(t *testing.T) {
+	if def, ok := oops.LookupCode("NOT_FOUND"); !ok || def.Label != userNotFound {
+		t.Fatalf("expected the user label to own NOT_FOUND, got %+v", def)
+	}
+	if _, ok := oops.Lookup(oops.NotFound); ok {
+		t.Errorf("expected the stdlib labels not to be registered by default")
+	}
+	got := oops.Handle(fs.ErrNotExist, oops.Stdlib()...)
+	if !errors.Is(got, oops.NotFound) || errors.Is(got, userNotFound) {
+		t.Errorf("expected the stdlib label, got %+v", got)
+	}
+	if decoded := roundTrip(t, got); decoded.Label() != oops.NotFound {
+		t.Errorf("expected the unregistered stdlib label to be decoded as itself, got %#v", decoded.Label())
+	}
+	if decoded := roundTrip(t, oops.New("missing", oops.Tag(userNotFound))); decoded.Label() != userNotFound {
+		t.Errorf("expected the user label to be decoded by its code, got %#v", decoded.Label())
+	}
+	if code := encodedCode(t, got); code != "oops.NOT_FOUND" {
+		t.Errorf("expected the reserved code of the unregistered stdlib label, got %q", code)
+	}
+	invalid := oops.New("bad request", oops.Tag(errors.New("invalid input")))
+	if decoded := roundTrip(t, invalid); errors.Is(decoded, oops.InvalidInput) {
+		t.Errorf("expected an unregistered label with the text of a stdlib label not to be decoded as it, got %#v", decoded.Label())
+	}
+	timeout := oops.Handle(&net.OpError{Op: "dial", Err: timeoutErr{timeout: true}}, oops.Stdlib()...).(*oops.Error)
+	data, _ := timeout.MarshalBinary()
+	var decoded oops.Error
+	if err := decoded.UnmarshalBinary(data); err != nil || decoded.Label() != oops.Timeout {
+		t.Errorf("expected the binary encoding to keep the stdlib label, got %#v, %v", decoded.Label(), err)
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	testCases := []struct {
+		err  error
+		want bool
+	}{
+		{err: oops.New("conflict", oops.Tag(oops.Aborted)), want: true},
+		{err: oops.Handle(&net.OpError{Op: "dial", Err: timeoutErr{}}, oops.Stdlib()...), want: true},
+		{err: oops.Handle(&net.OpError{Op: "dial", Err: timeoutErr{timeout: true}}, oops.Stdlib()...), want: true},
+		{err: oops.Handle(&url.Error{Op: "Post", URL: "http://example.com", Err: io.EOF}, oops.Stdlib()...), want: true},
+		{err: oops.Handle(sql.ErrNoRows, oops.Stdlib()...), want: false},
+		{err: oops.New("wrapped", oops.Because(oops.New("conflict", oops.Tag(oops.Aborted)))), want: true},
+		{err: errors.New("plain"), want: false},
+		{err: nil, want: false},
+	}
+	for _, tc := range testCases {
+		if got := oops.Retryable(tc.err); got != tc.want {
+			t.Errorf("Retryable(%v) = %v, want %v", tc.err, got, tc.want)
+		}
+	}
+}
+
+// encodedCode returns the code of the label of err as encoded by [oops.Error.MarshalJSON].
+func encodedCode(t *testing.T, err error) string {
+	t.Helper()
+	data, jsonErr := json.Marshal(err)
+	if jsonErr != nil {
+		t.Fatalf("unexpected error: %v", jsonErr)
+	}
+	var v struct{ Code string }
+	json.Unmarshal(data, &v)
+	return v.Code
+}
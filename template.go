@@ -0,0 +1,150 @@
+package oops
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Param is a named parameter of a message template, see [Newf] and [Bind].
+type Param struct {
+	Name  string
+	Value any
+}
+
+// Newf creates a new *[Error] like [New], with a message template whose {name} placeholders are bound,
+// in order of appearance, to the given args:
+//
+//	oops.Newf("user {id} not found in {table}", 42, "users", oops.Tag(example.NotFound.Error))
+//
+// Args of type [ErrorOption] are applied as options instead of being bound. Extra args are bound as
+// "arg<position>" parameters, and placeholders without an arg are left as is.
+// The template and the parameters are kept separately, see [Error.Template] and [Error.Params],
+// and the message is only rendered by [Error.Error].
+func Newf(template string, args ...any) error {
+	return newf(1, template, args, nil)
+}
+
+// newf creates the *[Error] of [Newf] skip frames above its caller, applying the given options before the args.
+func newf(skip int, template string, args []any, options []ErrorOption) *Error {
+	options = options[:len(options):len(options)]
+	names := placeholders(template)
+	bound := 0
+	for _, arg := range args {
+		if option, ok := arg.(ErrorOption); ok {
+			options = append(options, option)
+			continue
+		}
+		name := fmt.Sprintf("arg%d", bound+1)
+		if bound < len(names) {
+			name = names[bound]
+		}
+		options = append(options, Bind(name, arg))
+		bound++
+	}
+	return newError(skip+1, template, options)
+}
+
+// Bind binds a value to the {name} placeholder of the message template given in the [New] function.
+// Binding an existing name overwrites its value.
+func Bind(name string, value any) ErrorOption {
+	return func(err *Error) {
+		for i := range err.params {
+			if err.params[i].Name == name {
+				err.params[i].Value = value
+				return
+			}
+		}
+		err.params = append(err.params, Param{Name: name, Value: value})
+	}
+}
+
+// Template returns the message template given in the [New] or [Newf] function, without the parameters rendered.
+func (err *Error) Template() string { return err.msg }
+
+// Params returns a copy of the parameters bound to the message template.
+func (err *Error) Params() []Param {
+	if len(err.params) == 0 {
+		return nil
+	}
+	return append([]Param(nil), err.params...)
+}
+
+// render replaces the {name} placeholders of the template with the bound parameters.
+func (err *Error) render() string {
+	if len(err.params) == 0 {
+		return err.msg
+	}
+	var b strings.Builder
+	rest := err.msg
+	for {
+		start, end, name := nextPlaceholder(rest)
+		if start < 0 {
+			b.WriteString(rest)
+			return b.String()
+		}
+		b.WriteString(rest[:start])
+		if value, ok := err.param(name); ok {
+			fmt.Fprint(&b, value)
+		} else {
+			b.WriteString(rest[start:end])
+		}
+		rest = rest[end:]
+	}
+}
+
+func (err *Error) param(name string) (any, bool) {
+	for i := range err.params {
+		if err.params[i].Name == name {
+			return err.params[i].Value, true
+		}
+	}
+	return nil, false
+}
+
+// placeholders returns the distinct placeholder names of a template, in order of appearance.
+func placeholders(template string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for {
+		start, end, name := nextPlaceholder(template)
+		if start < 0 {
+			return names
+		}
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+		template = template[end:]
+	}
+}
+
+// nextPlaceholder returns the bounds and the name of the first {name} placeholder of s, or -1 if there is none.
+// A name starts with a letter or an underscore, followed by letters, digits, underscores or dots.
+func nextPlaceholder(s string) (start, end int, name string) {
+	for offset := 0; ; {
+		i := strings.IndexByte(s[offset:], '{')
+		if i < 0 {
+			return -1, -1, ""
+		}
+		start = offset + i
+		j := start + 1
+		for j < len(s) && isNameByte(s[j], j == start+1) {
+			j++
+		}
+		if j > start+1 && j < len(s) && s[j] == '}' {
+			return start, j + 1, s[start+1 : j]
+		}
+		offset = start + 1
+	}
+}
+
+func isNameByte(c byte, first bool) bool {
+	switch {
+	case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+		return true
+	case c >= '0' && c <= '9' || c == '.':
+		return !first
+	default:
+		return false
+	}
+}
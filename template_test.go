@@ -0,0 +1,72 @@
+package oops_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/piteego/oops"
+	"github.com/piteego/oops/example"
+)
+
+func TestNewf(t *testing.T) {
+	testCases := []struct {
+		name     string
+		template string
+		args     []any
+		want     string
+		params   []oops.Param
+	}{
+		{"no placeholders", "user not found", nil, "user not found", nil},
+		{"positional", "user {id} not found in {table}", []any{42, "users"}, "user 42 not found in users", []oops.Param{{"id", 42}, {"table", "users"}}},
+		{"repeated", "{id}: user {id} not found", []any{42}, "42: user 42 not found", []oops.Param{{"id", 42}}},
+		{"missing arg", "user {id} not found in {table}", []any{42}, "user 42 not found in {table}", []oops.Param{{"id", 42}}},
+		{"extra arg", "user {id} not found", []any{42, "extra"}, "user 42 not found", []oops.Param{{"id", 42}, {"arg2", "extra"}}},
+		{"not placeholders", "{} {1st} { id } {user.id}", []any{7}, "{} {1st} { id } 7", []oops.Param{{"user.id", 7}}},
+		{"options", "user {id} not found", []any{oops.Tag(example.NotFound.Error), 42, oops.With("k", "v")}, "user 42 not found", []oops.Param{{"id", 42}}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := oops.Newf(tc.template, tc.args...).(*oops.Error)
+			if got.Error() != tc.want {
+				t.Errorf("expected %q, got %q", tc.want, got.Error())
+			}
+			if got.Template() != tc.template {
+				t.Errorf("expected template %q, got %q", tc.template, got.Template())
+			}
+			if fmt.Sprint(got.Params()) != fmt.Sprint(tc.params) {
+				t.Errorf("expected params %v, got %v", tc.params, got.Params())
+			}
+		})
+	}
+	t.Run("options applied", func(t *testing.T) {
+		got := oops.Newf("user {id} not found", 42, oops.Tag(example.NotFound.Error)).(*oops.Error)
+		if got.Label() != example.NotFound.Error {
+			t.Errorf("expected label %q, got %q", example.NotFound.Error, got.Label())
+		}
+	})
+}
+
+func TestBind(t *testing.T) {
+	got := oops.New("user {id} not found", oops.Bind("id", 1), oops.Bind("id", 42)).(*oops.Error)
+	if got.Error() != "user 42 not found" || len(got.Params()) != 1 {
+		t.Errorf("expected overwritten param, got %q %v", got, got.Params())
+	}
+	data, _ := json.Marshal(got)
+	if !strings.Contains(string(data), `"message":"user 42 not found","label":"untagged","template":"user {id} not found","params":{"id":42}`) {
+		t.Errorf("expected template and params in %s", data)
+	}
+	if verbose := fmt.Sprintf("%+v", got); !strings.Contains(verbose, "    template: user {id} not found\n    params:\n        id: 42") {
+		t.Errorf("expected template and params in %q", verbose)
+	}
+}
+
+func TestNewf_Fingerprint(t *testing.T) {
+	newUserErr := func(id any) *oops.Error {
+		return oops.Newf("user {id} not found", id, oops.Tag(example.NotFound.Error)).(*oops.Error)
+	}
+	if newUserErr("alice").Fingerprint() != newUserErr("bob").Fingerprint() {
+		t.Errorf("expected parameters not to alter the fingerprint")
+	}
+}
@@ -0,0 +1,76 @@
+package oops
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+// ErrInvalidTraceParent is returned by [ParseTraceParent] for malformed headers.
+var ErrInvalidTraceParent = errors.New("invalid traceparent")
+
+// TraceParent is a parsed W3C Trace Context traceparent header.
+// See https://www.w3.org/TR/trace-context/#traceparent-header
+type TraceParent struct {
+	Version byte
+	TraceID string
+	SpanID  string
+	Flags   byte
+}
+
+// ParseTraceParent parses a W3C traceparent header value, e.g.
+// "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01".
+// Headers of future versions are accepted as long as their known prefix is valid.
+func ParseTraceParent(header string) (TraceParent, error) {
+	const size = 55 // len("vv-" + 32 hex trace id + "-" + 16 hex span id + "-ff")
+	header = strings.TrimSpace(header)
+	if len(header) < size || header[2] != '-' || header[35] != '-' || header[52] != '-' {
+		return TraceParent{}, ErrInvalidTraceParent
+	}
+	version, ok := hexByte(header[0:2])
+	if !ok || version == 0xff || (version == 0 && len(header) != size) || (len(header) > size && header[size] != '-') {
+		return TraceParent{}, ErrInvalidTraceParent
+	}
+	flags, ok := hexByte(header[53:55])
+	if !ok {
+		return TraceParent{}, ErrInvalidTraceParent
+	}
+	tp := TraceParent{Version: version, TraceID: header[3:35], SpanID: header[36:52], Flags: flags}
+	if !isHexID(tp.TraceID) || !isHexID(tp.SpanID) {
+		return TraceParent{}, ErrInvalidTraceParent
+	}
+	return tp, nil
+}
+
+// Sampled reports whether the sampled flag is set.
+func (tp TraceParent) Sampled() bool { return tp.Flags&0x01 == 0x01 }
+
+// String returns the traceparent header value.
+func (tp TraceParent) String() string {
+	return hex.EncodeToString([]byte{tp.Version}) + "-" + tp.TraceID + "-" + tp.SpanID + "-" + hex.EncodeToString([]byte{tp.Flags})
+}
+
+func hexByte(s string) (byte, bool) {
+	if !isLowerHex(s) {
+		return 0, false
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return 0, false
+	}
+	return b[0], true
+}
+
+// isHexID reports whether s is a valid lowercase hex identifier that is not all zeros.
+func isHexID(s string) bool {
+	return isLowerHex(s) && strings.Trim(s, "0") != ""
+}
+
+func isLowerHex(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if (s[i] < '0' || s[i] > '9') && (s[i] < 'a' || s[i] > 'f') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,109 @@
+package oops
+
+import "reflect"
+
+// Walk calls fn for err and each of its causes, depth-first and in order, with the depth of the error in the tree
+// (0 for err itself). Causes of an *[Error] are the ones of [Error.Causes], and causes of other errors are the ones returned by
+// their Unwrap() error or Unwrap() []error method. Walk stops as soon as fn returns false.
+// An error already being visited up the tree is not visited again, which protects against cycles.
+func Walk(err error, fn func(depth int, e error) bool) {
+	if err == nil {
+		return
+	}
+	walk(err, 0, make(map[error]bool), fn)
+}
+
+func walk(err error, depth int, ancestors map[error]bool, fn func(int, error) bool) bool {
+	key, comparable := comparableKey(err)
+	if comparable {
+		if ancestors[key] {
+			return true
+		}
+		ancestors[key] = true
+		defer delete(ancestors, key)
+	}
+	if !fn(depth, err) {
+		return false
+	}
+	for _, cause := range causesOf(err) {
+		if cause != nil && !walk(cause, depth+1, ancestors, fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// causesOf returns the direct causes of err.
+func causesOf(err error) []error {
+	switch e := err.(type) {
+	case *Error:
+		return e.stack
+	case interface{ Unwrap() []error }:
+		return e.Unwrap()
+	case interface{ Unwrap() error }:
+		return []error{e.Unwrap()}
+	default:
+		return nil
+	}
+}
+
+// comparableKey returns err as a map key, if its dynamic type is comparable.
+func comparableKey(err error) (error, bool) {
+	return err, reflect.TypeOf(err).Comparable()
+}
+
+// Node is an error of a flattened error tree, see [Flatten].
+type Node struct {
+	Err   error
+	Depth int
+	// IsLabel reports whether Err is the [Label] of its parent *[Error], rather than one of its causes.
+	IsLabel bool
+}
+
+// Flatten returns the nodes of the err tree in [Walk] order, including the [Label] of each *[Error]
+// right after it with IsLabel set. An error shared by several branches of the tree is only listed once,
+// at its first occurrence.
+func Flatten(err error) []Node {
+	var nodes []Node
+	seen := make(map[error]bool)
+	Walk(err, func(depth int, e error) bool {
+		if key, comparable := comparableKey(e); comparable {
+			if seen[key] {
+				return true
+			}
+			seen[key] = true
+		}
+		nodes = append(nodes, Node{Err: e, Depth: depth})
+		if oopsErr, ok := e.(*Error); ok && oopsErr.label != nil {
+			nodes = append(nodes, Node{Err: oopsErr.label, Depth: depth + 1, IsLabel: true})
+		}
+		return true
+	})
+	return nodes
+}
+
+// Root returns the deepest cause of err, the first one in [Walk] order if there are several at the same depth.
+// Labels are never returned as root cause. It returns err itself if it has no cause, or nil if err is nil.
+func Root(err error) error {
+	root, rootDepth := err, 0
+	Walk(err, func(depth int, e error) bool {
+		if depth > rootDepth {
+			root, rootDepth = e, depth
+		}
+		return true
+	})
+	return root
+}
+
+// Find returns the first error of the err tree, in [Walk] order, whose type is T. Labels are not searched.
+//
+//	if numErr, ok := oops.Find[*strconv.NumError](err); ok { ... }
+func Find[T error](err error) (T, bool) {
+	var found T
+	ok := false
+	Walk(err, func(_ int, e error) bool {
+		found, ok = e.(T)
+		return !ok
+	})
+	return found, ok
+}
@@ -0,0 +1,143 @@
+package oops_test
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/piteego/oops"
+	"github.com/piteego/oops/example"
+)
+
+// tree returns:
+//
+//	failed to process [internal]
+//	  user not found [not found]
+//	    query failed: %w
+//	      *strconv.NumError
+//	        strconv.ErrSyntax
+//	  shared
+//	  errors.Join
+//	    shared
+//	    fs.ErrNotExist
+func tree() (error, error) {
+	_, numErr := strconv.Atoi("x")
+	shared := errors.New("shared")
+	inner := oops.New("user not found", oops.Tag(example.NotFound.Error), oops.Because(fmt.Errorf("query failed: %w", numErr)))
+	return oops.New("failed to process",
+		oops.Tag(example.Internal.Error),
+		oops.Because(inner, shared, errors.Join(shared, fs.ErrNotExist)),
+	), shared
+}
+
+func TestWalk(t *testing.T) {
+	err, _ := tree()
+	var got []string
+	oops.Walk(err, func(depth int, e error) bool {
+		got = append(got, fmt.Sprintf("%d:%s", depth, strings.SplitN(e.Error(), "\n", 2)[0]))
+		return true
+	})
+	want := []string{
+		"0:failed to process",
+		"1:user not found",
+		`2:query failed: strconv.Atoi: parsing "x": invalid syntax`,
+		`3:strconv.Atoi: parsing "x": invalid syntax`,
+		"4:invalid syntax",
+		"1:shared",
+		"1:shared",
+		"2:shared",
+		"2:file does not exist",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("expected:\n%s\ngot:\n%s", strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+	visited := 0
+	oops.Walk(err, func(depth int, e error) bool {
+		visited++
+		return depth < 2
+	})
+	if visited != 3 {
+		t.Errorf("expected the walk to stop at depth 2 after 3 visits, got %d", visited)
+	}
+	oops.Walk(nil, func(int, error) bool {
+		t.Errorf("expected nil error not to be visited")
+		return true
+	})
+}
+
+func TestWalk_Cycle(t *testing.T) {
+	err := oops.New("cyclic").(*oops.Error)
+	oops.Because(err)(err)
+	visited := 0
+	oops.Walk(err, func(int, error) bool {
+		visited++
+		return true
+	})
+	if visited != 1 {
+		t.Errorf("expected cycle to be cut, got %d visits", visited)
+	}
+	if nodes := oops.Flatten(err); len(nodes) != 2 {
+		t.Errorf("expected the error and its label, got %v", nodes)
+	}
+}
+
+func TestFlatten(t *testing.T) {
+	err, shared := tree()
+	nodes := oops.Flatten(err)
+	var labels, sharedCount int
+	for _, node := range nodes {
+		if node.IsLabel {
+			labels++
+			if node.Err != example.Internal.Error && node.Err != example.NotFound.Error {
+				t.Errorf("unexpected label node %v", node)
+			}
+		}
+		if node.Err == shared {
+			sharedCount++
+		}
+	}
+	if len(nodes) != 10 || labels != 2 || sharedCount != 1 {
+		t.Errorf("expected 10 nodes with 2 labels and the shared error once, got %d nodes, %d labels and %d shared", len(nodes), labels, sharedCount)
+	}
+	if !nodes[1].IsLabel || nodes[1].Depth != 1 || nodes[1].Err != example.Internal.Error {
+		t.Errorf("expected the label right after its error, got %+v", nodes[1])
+	}
+}
+
+func TestRoot(t *testing.T) {
+	err, _ := tree()
+	if got := oops.Root(err); got != strconv.ErrSyntax {
+		t.Errorf("expected %v, got %v", strconv.ErrSyntax, got)
+	}
+	untagged := oops.New("no causes")
+	if got := oops.Root(untagged); got != untagged {
+		t.Errorf("expected the error itself, got %v", got)
+	}
+	if got := oops.Root(nil); got != nil {
+		t.Errorf("expected nil, got %v", got)
+	}
+}
+
+func TestFind(t *testing.T) {
+	err, _ := tree()
+	numErr, ok := oops.Find[*strconv.NumError](err)
+	if !ok || numErr.Func != "Atoi" {
+		t.Errorf("expected *strconv.NumError, got %v", numErr)
+	}
+	oopsErr, ok := oops.Find[*oops.Error](err)
+	if !ok || oopsErr.Error() != "failed to process" {
+		t.Errorf("expected the outermost *oops.Error, got %v", oopsErr)
+	}
+	if _, ok := oops.Find[*fs.PathError](err); ok {
+		t.Errorf("expected no *fs.PathError")
+	}
+	if _, ok := oops.Find[interface {
+		error
+		Unwrap() []error
+	}](err); !ok {
+		t.Errorf("expected interface types to be found")
+	}
+}
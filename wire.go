@@ -0,0 +1,336 @@
+package oops
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+)
+
+// MaxBinarySize bounds the size of the binary encoding of an *[Error], see [Error.MarshalBinary].
+const MaxBinarySize = 1 << 20
+
+// ErrInvalidBinary is returned by [Error.UnmarshalBinary] for malformed, oversized or too deeply nested input.
+var ErrInvalidBinary = errors.New("invalid binary encoding")
+
+// The binary encoding starts with binaryMagic, followed by its version.
+// Version 2 added the occurrence and the hops, and version 3 the instance ID; older versions are still decoded.
+const (
+	binaryMagic   = 'o'
+	binaryVersion = 3
+)
+
+// Tags of the nodes and of the attribute and parameter values in the binary encoding.
+const (
+	wireCause byte = iota // a cause that is not an *Error: its message only
+	wireError             // an *Error
+)
+
+const (
+	wireNil byte = iota
+	wireString
+	wireInt
+	wireUint
+	wireFloat
+	wireBool
+)
+
+// MarshalBinary implements [encoding.BinaryMarshaler]. It encodes the same error tree as [Error.MarshalJSON],
+// redacted the same way, in a compact versioned format: a magic byte and a version byte, followed by the nodes
+// of the tree, whose strings and lists are prefixed by their length as unsigned varints.
+// Attribute and parameter values are encoded as strings, integers, floats or booleans; other types as strings.
+// It fails if the encoding is larger than [MaxBinarySize].
+func (err *Error) MarshalBinary() ([]byte, error) {
+	e := wireEncoder{buf: []byte{binaryMagic, binaryVersion}}
+	redacting.appendBinary(&e, err, 0)
+	if len(e.buf) > MaxBinarySize {
+		return nil, fmt.Errorf("oops: binary encoding of %d bytes exceeds %d bytes", len(e.buf), MaxBinarySize)
+	}
+	return e.buf, nil
+}
+
+// UnmarshalBinary implements [encoding.BinaryUnmarshaler], decoding an *[Error] encoded by [Error.MarshalBinary],
+// typically in another process, like [Error.UnmarshalJSON] does. It is safe to use on untrusted input:
+// input larger than [MaxBinarySize] or nested deeper than the formatting depth is rejected with [ErrInvalidBinary].
+func (err *Error) UnmarshalBinary(data []byte) error {
+	if len(data) > MaxBinarySize {
+		return fmt.Errorf("%w: %d bytes exceed %d bytes", ErrInvalidBinary, len(data), MaxBinarySize)
+	}
+	if len(data) < 2 || data[0] != binaryMagic {
+		return fmt.Errorf("%w: missing header", ErrInvalidBinary)
+	}
+	if data[1] < 1 || data[1] > binaryVersion {
+		return fmt.Errorf("%w: unsupported version %d", ErrInvalidBinary, data[1])
+	}
+	d := wireDecoder{data: data[2:], version: data[1]}
+	if d.byte() != wireError {
+		d.fail("expected an error node")
+	}
+	decoded := d.error(0)
+	if d.err == nil && len(d.data) > 0 {
+		d.fail("trailing bytes")
+	}
+	if d.err != nil {
+		return d.err
+	}
+	*err = *decoded
+	return nil
+}
+
+func (r renderer) appendBinary(e *wireEncoder, err *Error, depth int) {
+	e.byte(wireError)
+	if len(err.params) > 0 {
+		e.string(r.text(err.msg))
+	} else {
+		e.string(r.text(err.Error()))
+	}
+	e.string(labelText(err.label))
+	e.string(labelCode(err.label))
+	e.string(err.id)
+	e.uvarint(uint64(len(err.params)))
+	for _, param := range err.params {
+		e.string(param.Name)
+		e.value(r.value(param.Value))
+	}
+	e.string(err.correlation.RequestID)
+	e.string(err.correlation.TraceID)
+	e.string(err.correlation.SpanID)
+	e.time(err.occurrence.Time)
+	e.string(err.occurrence.Host)
+	e.buf = binary.AppendVarint(e.buf, int64(err.occurrence.PID))
+	e.uvarint(uint64(len(err.hops)))
+	for _, hop := range err.hops {
+		e.string(hop.Name)
+		e.time(hop.Time)
+	}
+	e.uvarint(uint64(len(err.attrs)))
+	for _, attr := range err.attrs {
+		e.string(attr.Key)
+		e.value(r.attr(attr))
+	}
+	e.uvarint(uint64(len(err.frames)))
+	for _, frame := range err.frames {
+		e.string(frame.Function)
+		e.string(frame.File)
+		e.uvarint(uint64(frame.Line))
+	}
+	e.uvarint(uint64(len(err.stack)))
+	for _, cause := range err.stack {
+		if oopsErr, ok := cause.(*Error); ok && depth < maxDepth {
+			r.appendBinary(e, oopsErr, depth+1)
+			continue
+		}
+		e.byte(wireCause)
+		e.string(r.text(cause.Error()))
+	}
+}
+
+type wireEncoder struct {
+	buf []byte
+}
+
+func (e *wireEncoder) byte(b byte)      { e.buf = append(e.buf, b) }
+func (e *wireEncoder) uvarint(v uint64) { e.buf = binary.AppendUvarint(e.buf, v) }
+
+func (e *wireEncoder) string(s string) {
+	e.uvarint(uint64(len(s)))
+	e.buf = append(e.buf, s...)
+}
+
+// time encodes t as nanoseconds since the Unix epoch, or 0 for the zero time.
+func (e *wireEncoder) time(t time.Time) {
+	var nanos int64
+	if !t.IsZero() {
+		nanos = t.UnixNano()
+	}
+	e.buf = binary.AppendVarint(e.buf, nanos)
+}
+
+func (e *wireEncoder) value(v any) {
+	switch v := v.(type) {
+	case nil:
+		e.byte(wireNil)
+	case string:
+		e.byte(wireString)
+		e.string(v)
+	case bool:
+		e.byte(wireBool)
+		if v {
+			e.byte(1)
+		} else {
+			e.byte(0)
+		}
+	case int, int8, int16, int32, int64:
+		e.byte(wireInt)
+		e.buf = binary.AppendVarint(e.buf, reflect.ValueOf(v).Int())
+	case uint, uint8, uint16, uint32, uint64, uintptr:
+		e.byte(wireUint)
+		e.uvarint(reflect.ValueOf(v).Uint())
+	case float32, float64:
+		e.byte(wireFloat)
+		e.buf = binary.LittleEndian.AppendUint64(e.buf, math.Float64bits(reflect.ValueOf(v).Float()))
+	default:
+		e.byte(wireString)
+		e.string(fmt.Sprint(v))
+	}
+}
+
+// wireDecoder decodes the binary encoding, keeping the first error met: once it failed, it only returns zero values.
+type wireDecoder struct {
+	data    []byte
+	version byte
+	err     error
+}
+
+func (d *wireDecoder) fail(reason string) {
+	if d.err == nil {
+		d.err = fmt.Errorf("%w: %s", ErrInvalidBinary, reason)
+	}
+	d.data = nil
+}
+
+func (d *wireDecoder) byte() byte {
+	if len(d.data) == 0 {
+		d.fail("unexpected end of input")
+		return 0
+	}
+	b := d.data[0]
+	d.data = d.data[1:]
+	return b
+}
+
+func (d *wireDecoder) uvarint() uint64 {
+	v, n := binary.Uvarint(d.data)
+	if n <= 0 {
+		d.fail("malformed varint")
+		return 0
+	}
+	d.data = d.data[n:]
+	return v
+}
+
+func (d *wireDecoder) varint() int64 {
+	v, n := binary.Varint(d.data)
+	if n <= 0 {
+		d.fail("malformed varint")
+		return 0
+	}
+	d.data = d.data[n:]
+	return v
+}
+
+// count decodes the length of a list, which cannot exceed the remaining bytes as every element takes one at least.
+func (d *wireDecoder) count() int {
+	n := d.uvarint()
+	if n > uint64(len(d.data)) {
+		d.fail("length out of range")
+		return 0
+	}
+	return int(n)
+}
+
+func (d *wireDecoder) string() string {
+	n := d.count()
+	s := string(d.data[:n])
+	d.data = d.data[n:]
+	return s
+}
+
+func (d *wireDecoder) time() time.Time {
+	if nanos := d.varint(); nanos != 0 {
+		return time.Unix(0, nanos).UTC()
+	}
+	return time.Time{}
+}
+
+func (d *wireDecoder) value() any {
+	switch tag := d.byte(); tag {
+	case wireNil:
+		return nil
+	case wireString:
+		return d.string()
+	case wireInt:
+		return d.varint()
+	case wireUint:
+		return d.uvarint()
+	case wireFloat:
+		if len(d.data) < 8 {
+			d.fail("unexpected end of input")
+			return nil
+		}
+		v := math.Float64frombits(binary.LittleEndian.Uint64(d.data))
+		d.data = d.data[8:]
+		return v
+	case wireBool:
+		switch d.byte() {
+		case 0:
+			return false
+		case 1:
+			return true
+		}
+		d.fail("malformed bool")
+		return nil
+	default:
+		d.fail(fmt.Sprintf("unknown value tag %d", tag))
+		return nil
+	}
+}
+
+// error decodes an *Error node whose tag has already been read.
+func (d *wireDecoder) error(depth int) *Error {
+	if depth > maxDepth {
+		d.fail("too deeply nested")
+		return nil
+	}
+	err := &Error{msg: d.string()}
+	text, code := d.string(), d.string()
+	err.label = remoteLabel(text, code)
+	if d.version >= 3 {
+		err.id = d.string()
+	}
+	for i, n := 0, d.count(); i < n && d.err == nil; i++ {
+		name := d.string()
+		err.params = append(err.params, Param{Name: name, Value: d.value()})
+	}
+	err.correlation = Correlation{RequestID: d.string(), TraceID: d.string(), SpanID: d.string()}
+	if d.version >= 2 {
+		err.occurrence = Occurrence{Time: d.time(), Host: d.string()}
+		pid := d.varint()
+		if pid < math.MinInt32 || pid > math.MaxInt32 {
+			d.fail("pid out of range")
+		}
+		err.occurrence.PID = int(pid)
+		for i, n := 0, d.count(); i < n && d.err == nil; i++ {
+			name := d.string()
+			err.hops = append(err.hops, Hop{Name: name, Time: d.time()})
+		}
+	}
+	for i, n := 0, d.count(); i < n && d.err == nil; i++ {
+		key := d.string()
+		err.attrs = append(err.attrs, Attr{Key: key, Value: d.value()})
+	}
+	for i, n := 0, d.count(); i < n && d.err == nil; i++ {
+		frame := Frame{Function: d.string(), File: d.string()}
+		line := d.uvarint()
+		if line > math.MaxInt32 {
+			d.fail("line out of range")
+		}
+		frame.Line = int(line)
+		err.frames = append(err.frames, frame)
+	}
+	for i, n := 0, d.count(); i < n && d.err == nil; i++ {
+		switch tag := d.byte(); tag {
+		case wireCause:
+			err.stack = append(err.stack, errors.New(d.string()))
+		case wireError:
+			if cause := d.error(depth + 1); cause != nil {
+				err.stack = append(err.stack, cause)
+			}
+		default:
+			d.fail(fmt.Sprintf("unknown node tag %d", tag))
+		}
+	}
+	return err
+}
@@ -0,0 +1,137 @@
+package oops_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/piteego/oops"
+	"github.com/piteego/oops/example"
+)
+
+func wireSample() error {
+	var err error
+	func() {
+		defer oops.Recover(&err)
+		panic("boom")
+	}()
+	inner := oops.Newf("user {id} not found", 42, oops.Tag(example.NotFound.Error), oops.Because(err))
+	return oops.New("failed to process",
+		oops.Tag(example.Internal.Error),
+		oops.With("user", "alice"),
+		oops.With("admin", true),
+		oops.With("ratio", 0.5),
+		oops.With("count", uint8(3)),
+		oops.With("tags", []string{"a", "b"}),
+		oops.Sensitive("password", "hunter2"),
+		oops.Because(inner, errors.New("plain cause")),
+	)
+}
+
+func TestError_MarshalBinary(t *testing.T) {
+	err := wireSample()
+	data, binErr := err.(*oops.Error).MarshalBinary()
+	if binErr != nil {
+		t.Fatalf("unexpected error: %v", binErr)
+	}
+	jsonData, _ := json.Marshal(err)
+	if len(data) >= len(jsonData) {
+		t.Errorf("expected the binary encoding (%d bytes) to be smaller than JSON (%d bytes)", len(data), len(jsonData))
+	}
+	var decoded oops.Error
+	if binErr := decoded.UnmarshalBinary(data); binErr != nil {
+		t.Fatalf("unexpected error: %v", binErr)
+	}
+	if got, want := fmt.Sprintf("%+v", &decoded), fmt.Sprintf("%+v", err); got != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, got)
+	}
+	if !errors.Is(&decoded, example.Internal.Error) || !errors.Is(&decoded, example.NotFound.Error) || !errors.Is(&decoded, oops.Panicked) {
+		t.Errorf("expected the decoded error to match the local labels")
+	}
+	if value, _ := decoded.Attr("password"); value != oops.Redacted {
+		t.Errorf("expected the sensitive attr to be redacted, got %v", value)
+	}
+	for key, want := range map[string]any{"admin": true, "ratio": 0.5, "count": uint64(3), "tags": "[a b]"} {
+		if got, _ := decoded.Attr(key); got != want {
+			t.Errorf("expected attr %s %#v, got %#v", key, want, got)
+		}
+	}
+	var inner *oops.Error
+	if !errors.As(decoded.Causes()[0], &inner) || len(inner.Causes()[0].(*oops.Error).Frames()) == 0 {
+		t.Errorf("expected the frames of the recovered panic")
+	}
+}
+
+func TestError_UnmarshalBinary_invalid(t *testing.T) {
+	valid, _ := oops.New("failed", oops.With("key", "value")).(*oops.Error).MarshalBinary()
+	deep := oops.New("root")
+	for i := 0; i < 40; i++ {
+		deep = oops.New("wrapped", oops.Because(deep))
+	}
+	deepData, _ := deep.(*oops.Error).MarshalBinary()
+	testCases := []struct {
+		name string
+		data []byte
+	}{
+		{name: "empty", data: nil},
+		{name: "bad magic", data: []byte{'x', 1}},
+		{name: "unknown version", data: []byte{'o', 99}},
+		{name: "truncated", data: valid[:len(valid)-3]},
+		{name: "trailing bytes", data: append(append([]byte(nil), valid...), 0)},
+		{name: "huge length", data: []byte{'o', 1, 1, 0xff, 0xff, 0xff, 0xff, 0x0f}},
+		{name: "oversized", data: append([]byte{'o', 1}, make([]byte, oops.MaxBinarySize)...)},
+		{name: "too deep", data: nestedBinary(40)},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var decoded oops.Error
+			if err := decoded.UnmarshalBinary(tc.data); !errors.Is(err, oops.ErrInvalidBinary) {
+				t.Errorf("expected ErrInvalidBinary, got %v", err)
+			}
+		})
+	}
+	t.Run("encoding caps the depth", func(t *testing.T) {
+		var decoded oops.Error
+		if err := decoded.UnmarshalBinary(deepData); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
+
+// nestedBinary returns an encoding of errors nested depth times, each with a single cause.
+func nestedBinary(depth int) []byte {
+	data := []byte{'o', 1}
+	for i := 0; i <= depth; i++ {
+		// error node: empty message, label and code, no params, correlation ids, attrs nor frames
+		data = append(data, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1)
+	}
+	return append(data[:len(data)-1], 0)
+}
+
+func FuzzError_UnmarshalBinary(f *testing.F) {
+	sample, _ := wireSample().(*oops.Error).MarshalBinary()
+	f.Add(sample)
+	f.Add(nestedBinary(3))
+	f.Add([]byte{'o', 1})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var decoded oops.Error
+		if err := decoded.UnmarshalBinary(data); err != nil {
+			return
+		}
+		// A decoded error encodes to the same bytes it decodes from.
+		encoded, err := decoded.MarshalBinary()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var again oops.Error
+		if err := again.UnmarshalBinary(encoded); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		reencoded, _ := again.MarshalBinary()
+		if !bytes.Equal(encoded, reencoded) {
+			t.Errorf("expected a stable encoding:\n%x\n%x", encoded, reencoded)
+		}
+	})
+}